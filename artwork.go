@@ -0,0 +1,77 @@
+package plexize
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Artwork is an image file of a movie, a TV show or a season, like cover.jpg or
+// season01-poster.jpg.
+type Artwork struct {
+	Kind   string // Like poster, fanart or banner.
+	Season string // Season of a season poster, like 01, or 00 for specials.
+	Ext    string // Lower case extension, like .jpg.
+}
+
+var artworkExts = map[string]bool{
+	".jpg": true, ".jpeg": true, ".png": true, ".tbn": true,
+}
+
+var (
+	posterRe       = regexp.MustCompile(`(?i)^(?:poster|cover|folder|default|movie|show)$`)
+	fanartRe       = regexp.MustCompile(`(?i)^(?:fanart|backdrop|background|art)$`)
+	bannerRe       = regexp.MustCompile(`(?i)^banner$`)
+	seasonPosterRe = regexp.MustCompile(`(?i)^season[ ._\-]?(\d{1,2}|specials)(?:[ ._\-]?(?:poster|cover))?$`)
+)
+
+// ParseArtwork parses an image file name next to a video file, and reports
+// whether it is an artwork. Names like poster, cover or folder are posters,
+// names like fanart or backdrop are fanarts, and names like Season01 or
+// season01-poster are season posters. They can have the stem of the video
+// file, like Movie.2019-poster.jpg for Movie.2019.mkv, but no other words, so
+// images like Some.Movie.jpg are not artworks.
+func ParseArtwork(video, filename string) (Artwork, bool) {
+	var a Artwork
+
+	_, f := filepath.Split(filename)
+	ext := filepath.Ext(f)
+	if !artworkExts[strings.ToLower(ext)] {
+		return a, false
+	}
+	a.Ext = strings.ToLower(ext)
+	stem := strings.TrimSuffix(f, ext)
+	if _, v := filepath.Split(video); v != "" {
+		stem = strings.TrimPrefix(stem, strings.TrimSuffix(v, filepath.Ext(v))+"-")
+	}
+
+	switch {
+	case seasonPosterRe.MatchString(stem):
+		s := seasonPosterRe.FindStringSubmatch(stem)[1]
+		a.Kind = "poster"
+		a.Season = "00"
+		if !strings.EqualFold(s, "specials") {
+			a.Season = fmt.Sprintf("%02s", s)
+		}
+	case posterRe.MatchString(stem):
+		a.Kind = "poster"
+	case fanartRe.MatchString(stem):
+		a.Kind = "fanart"
+	case bannerRe.MatchString(stem):
+		a.Kind = "banner"
+	default:
+		return a, false
+	}
+
+	return a, true
+}
+
+// Name returns the Plex local asset file name of the artwork, like poster.jpg,
+// fanart.jpg or Season01.jpg.
+func (a Artwork) Name() string {
+	if a.Season != "" {
+		return "Season" + a.Season + a.Ext
+	}
+	return a.Kind + a.Ext
+}
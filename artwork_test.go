@@ -0,0 +1,44 @@
+package plexize
+
+import "testing"
+
+func TestParseArtwork(t *testing.T) {
+	ts := []struct {
+		f  string
+		ok bool
+		a  Artwork
+		n  string
+	}{
+		{"cover.jpg", true, Artwork{Kind: "poster", Ext: ".jpg"}, "poster.jpg"},
+		{"folder.JPG", true, Artwork{Kind: "poster", Ext: ".jpg"}, "poster.jpg"},
+		{"Movie.2019-poster.png", true, Artwork{Kind: "poster", Ext: ".png"}, "poster.png"},
+		{"backdrop.png", true, Artwork{Kind: "fanart", Ext: ".png"}, "fanart.png"},
+		{"fanart.jpg", true, Artwork{Kind: "fanart", Ext: ".jpg"}, "fanart.jpg"},
+		{"banner.jpg", true, Artwork{Kind: "banner", Ext: ".jpg"}, "banner.jpg"},
+		{"season01-poster.jpg", true, Artwork{Kind: "poster", Season: "01", Ext: ".jpg"}, "Season01.jpg"},
+		{"Season 2.jpg", true, Artwork{Kind: "poster", Season: "02", Ext: ".jpg"}, "Season02.jpg"},
+		{"season-specials-poster.jpg", true, Artwork{Kind: "poster", Season: "00", Ext: ".jpg"}, "Season00.jpg"},
+		{"Movie.2019-fanart.jpg", true, Artwork{Kind: "fanart", Ext: ".jpg"}, "fanart.jpg"},
+		{"screenshot.jpg", false, Artwork{}, ""},
+		{"Some.Movie.jpg", false, Artwork{}, ""},
+		{"Other.2019-poster.jpg", false, Artwork{}, ""},
+		{"album-cover.jpg", false, Artwork{}, ""},
+		{"cover.txt", false, Artwork{}, ""},
+	}
+
+	for _, tt := range ts {
+		a, ok := ParseArtwork("Movie.2019.mkv", tt.f)
+		if ok != tt.ok {
+			t.Errorf("artwork: %s\ngot:  %v\nwant: %v", tt.f, ok, tt.ok)
+		}
+		if !ok {
+			continue
+		}
+		if a != tt.a {
+			t.Errorf("artwork: %s\ngot:  %+v\nwant: %+v", tt.f, a, tt.a)
+		}
+		if n := a.Name(); n != tt.n {
+			t.Errorf("name: %s\ngot:  %s\nwant: %s", tt.f, n, tt.n)
+		}
+	}
+}
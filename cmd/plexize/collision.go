@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Collision policies, what to do if the new path of a file exists, or is taken
+// by another file of the batch.
+const (
+	collisionSkip    = "skip"
+	collisionSuffix  = "suffix"
+	collisionReplace = "replace"
+	collisionPrompt  = "prompt"
+)
+
+var stdin = bufio.NewReader(os.Stdin)
+
+// batch is the new paths of files of a batch with their sizes, to detect
+// collisions of files of the batch in a dry run too.
+type batch map[string]int64
+
+// collisionFlag is a flag of a collision policy.
+type collisionFlag string
+
+func (c *collisionFlag) String() string {
+	return string(*c)
+}
+
+func (c *collisionFlag) Set(s string) error {
+	switch s {
+	case collisionSkip, collisionSuffix, collisionReplace, collisionPrompt:
+		*c = collisionFlag(s)
+		return nil
+	}
+	return fmt.Errorf("unknown collision policy %q (skip, suffix, replace or prompt)", s)
+}
+
+// resolve applies the collision policy to the new path of a file, and returns
+// the path to use, or false to skip the file.
+func resolve(path, newPath string, o options) (string, bool) {
+	if filepath.Clean(path) == filepath.Clean(newPath) {
+		return newPath, true
+	}
+	ns, ok := taken(newPath, o)
+	if !ok {
+		o.batch.claim(path, newPath)
+		return newPath, true
+	}
+
+	c, asked := string(o.collision), false
+	if c == collisionPrompt {
+		c, asked = prompt(newPath), true
+	}
+	switch c {
+	case collisionSuffix:
+		ext := filepath.Ext(newPath)
+		stem := strings.TrimSuffix(newPath, ext)
+		for i := 2; ; i++ {
+			np := fmt.Sprintf("%s (%d)%s", stem, i, ext)
+			if _, ok := taken(np, o); !ok {
+				log.Printf("%s exists, keeping both as %s\n", newPath, np)
+				o.batch.claim(path, np)
+				return np, true
+			}
+		}
+	case collisionReplace:
+		// Replace a larger file only if the user asks it.
+		fi, err := os.Stat(path)
+		if asked || err == nil && fi.Size() > ns {
+			log.Printf("%s exists, replacing it with %s\n", newPath, path)
+			o.batch.claim(path, newPath)
+			return newPath, true
+		}
+	}
+	log.Printf("%s exists, skipping %s\n", newPath, path)
+	return "", false
+}
+
+// replacedExt is the extension of a replaced file, which is kept to restore it
+// by undo.
+const replacedExt = ".plexize-replaced"
+
+// setAside moves an existing file at the new path of a file out of the way,
+// recording it in the journal, so undo can restore it. Without a journal, the
+// file is just replaced. It reports false if the file cannot be moved.
+func setAside(path, newPath string, o options) bool {
+	if o.journal == nil {
+		return true
+	}
+	nfi, err := os.Lstat(newPath)
+	if err != nil {
+		return true
+	}
+	// The same file on a case insensitive file system, like Movie.mkv for movie.mkv.
+	if fi, err := os.Lstat(path); err == nil && os.SameFile(fi, nfi) {
+		return true
+	}
+	ap := newPath + replacedExt
+	for i := 2; ; i++ {
+		if _, err := os.Lstat(ap); os.IsNotExist(err) {
+			break
+		}
+		ap = fmt.Sprintf("%s%s%d", newPath, replacedExt, i)
+	}
+	if err := os.Rename(newPath, ap); err != nil {
+		log.Printf("cannot set aside the replaced file: %v\n", err)
+		return false
+	}
+	o.journal.record(entry{Op: "rename", Path: newPath, New: ap})
+	log.Printf("%s is kept as %s to undo the run\n", newPath, ap)
+	return true
+}
+
+// taken returns the size of the file of a path if it exists, or it is taken by
+// another file of the batch.
+func taken(path string, o options) (int64, bool) {
+	if s, ok := o.batch[path]; ok {
+		return s, true
+	}
+	if fi, err := os.Stat(path); err == nil {
+		return fi.Size(), true
+	}
+	return 0, false
+}
+
+func (b batch) claim(path, newPath string) {
+	if b == nil {
+		return
+	}
+	var s int64
+	if fi, err := os.Stat(path); err == nil {
+		s = fi.Size()
+	}
+	b[newPath] = s
+}
+
+// prompt asks what to do with an existing file.
+func prompt(path string) string {
+	for {
+		fmt.Fprintf(os.Stderr, "%s exists, [s]kip, keep [b]oth or [r]eplace? ", path)
+		l, err := stdin.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(l)) {
+		case "b", "both":
+			return collisionSuffix
+		case "r", "replace":
+			return collisionReplace
+		case "s", "skip":
+			return collisionSkip
+		}
+		if err != nil {
+			return collisionSkip
+		}
+	}
+}
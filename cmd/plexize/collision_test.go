@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/m4ns0ur/plexize"
+)
+
+func TestResolve(t *testing.T) {
+	d, err := testDir("Movie (2019).mkv", "Movie (2019) (2).mkv")
+	if err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	defer os.RemoveAll(d)
+	if err := os.WriteFile(filepath.Join(d, "Movie.2019.1080p.mkv"), []byte("larger"), 0666); err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	if err := os.WriteFile(filepath.Join(d, "Movie.2019.720p.mkv"), nil, 0666); err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+
+	defer func(r *bufio.Reader) { stdin = r }(stdin)
+	stdin = bufio.NewReader(strings.NewReader("x\nb\nr\n"))
+
+	const mn = "Movie (2019).mkv"
+	ts := []struct {
+		p, np string
+		c     collisionFlag
+		b     batch
+		n     string
+	}{
+		{"Movie.2019.1080p.mkv", "Movie (2020).mkv", collisionSkip, nil, "Movie (2020).mkv"},
+		{"Movie.2019.1080p.mkv", mn, collisionSkip, nil, ""},
+		{"Movie.2019.1080p.mkv", mn, collisionSuffix, nil, "Movie (2019) (3).mkv"},
+		{"Movie.2019.1080p.mkv", mn, collisionReplace, nil, mn},
+		{"Movie.2019.720p.mkv", mn, collisionReplace, nil, ""},
+		{"Movie.2019.1080p.mkv", "Movie (2020).mkv", collisionSkip, batch{filepath.Join(d, "Movie (2020).mkv"): 0}, ""},
+		{"Movie.2019.720p.mkv", mn, collisionPrompt, nil, "Movie (2019) (3).mkv"},
+		{"Movie.2019.720p.mkv", mn, collisionPrompt, nil, mn},
+		{"Movie.2019.720p.mkv", mn, collisionPrompt, nil, ""},
+		{mn, mn, collisionSkip, nil, mn},
+	}
+
+	for _, tt := range ts {
+		var want string
+		if tt.n != "" {
+			want = filepath.Join(d, tt.n)
+		}
+		np, ok := resolve(filepath.Join(d, tt.p), filepath.Join(d, tt.np), options{collision: tt.c, batch: tt.b})
+		if np != want || ok != (want != "") {
+			t.Errorf("resolve: %s %s %s\ngot:  %s %v\nwant: %s %v", tt.p, tt.np, tt.c, np, ok, want, want != "")
+		}
+	}
+}
+
+func TestProcessCollision(t *testing.T) {
+	ps := []string{"Movie.2019.1080p.mkv", "Movie.2019.1080p.en.srt", "Movie.2019.720p.mkv", "Movie.2019.720p.en.srt"}
+
+	d, err := testDir(ps...)
+	if err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	defer os.RemoveAll(d)
+
+	o := options{separate: true, collision: collisionSuffix, batch: make(batch)}
+	processFiles([]string{filepath.Join(d, ps[0]), filepath.Join(d, ps[2])}, false, o)
+
+	for _, n := range []string{
+		filepath.Join(d, "Movie (2019)", "Movie (2019).mkv"),
+		filepath.Join(d, "Movie (2019)", "Movie (2019).en.srt"),
+		filepath.Join(d, "Movie (2019)", "Movie (2019) (2).mkv"),
+		filepath.Join(d, "Movie (2019)", "Movie (2019) (2).en.srt"),
+	} {
+		if _, err := os.Stat(n); os.IsNotExist(err) {
+			t.Errorf("file does not exist:  %v\n", err)
+		}
+	}
+}
+
+func TestProcessSkipNoDir(t *testing.T) {
+	const v = "Show.S01E02.720p.mkv"
+
+	d, err := testDir(v)
+	if err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	defer os.RemoveAll(d)
+
+	// The new path is taken by another file of the batch.
+	o := options{collision: collisionSkip, outDir: filepath.Join(d, "target"), batch: batch{filepath.Join(d, "target", "Show", "Season 01", "Show - s01e02.mkv"): 6}}
+	if np := process(filepath.Join(d, v), plexize.Media{}, o); np != "" {
+		t.Errorf("skipped: %s\ngot:  %s\nwant: ", v, np)
+	}
+	if _, err := os.Stat(filepath.Join(d, "target")); !os.IsNotExist(err) {
+		t.Errorf("dir exists:  %s\n", filepath.Join(d, "target"))
+	}
+}
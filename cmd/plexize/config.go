@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/m4ns0ur/plexize"
+)
+
+// config is the config file in a TOML subset: key = value pairs of strings,
+// booleans, integers and string arrays, in the root, [paths] and [templates]
+// tables. Flags override the config values.
+//
+//	owner = "plex"
+//	group = 1000
+//	change_mode = true
+//	change_owner = true
+//	file_mode = "0664"
+//	dir_mode = "2775"
+//	junk = ["www.site.org", "[site]"]
+//	profile = "jellyfin"
+//	transfer = "hardlink"
+//
+//	[paths]
+//	movie = "/srv/media/movies"
+//	show = "/srv/media/tv"
+//
+//	[templates]
+//	season = "Season {{num .Season}}"
+type config struct {
+	movieDir, showDir       string
+	owner, group            string
+	changeMode, changeOwner *bool
+	fileMode, dirMode       os.FileMode
+	junk                    []string
+	profile, transfer       string
+	templates               templatesFlag
+}
+
+// defaultConfigFile returns plexize/config.toml of the user config folder.
+func defaultConfigFile() string {
+	d, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(d, "plexize", "config.toml")
+}
+
+// loadConfig loads a config file. A missing file is an empty config if it is
+// not required.
+func loadConfig(path string, required bool) (config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) && !required {
+			return config{}, nil
+		}
+		return config{}, err
+	}
+	defer f.Close()
+	return parseConfig(f)
+}
+
+func parseConfig(r io.Reader) (config, error) {
+	var c config
+	table := ""
+	scanner := bufio.NewScanner(r)
+	for ln := 1; scanner.Scan(); ln++ {
+		l := strings.TrimSpace(stripComment(scanner.Text()))
+		if l == "" {
+			continue
+		}
+		if strings.HasPrefix(l, "[") && strings.HasSuffix(l, "]") {
+			table = strings.TrimSpace(l[1 : len(l)-1])
+			if table != "paths" && table != "templates" {
+				return c, fmt.Errorf("unknown table [%s] on line %d", table, ln)
+			}
+			continue
+		}
+
+		k, v, ok := strings.Cut(l, "=")
+		if !ok {
+			return c, fmt.Errorf("invalid line %d, want key = value", ln)
+		}
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if table != "" {
+			k = table + "." + k
+		}
+		if err := c.set(k, v); err != nil {
+			return c, fmt.Errorf("invalid %s on line %d: %v", k, ln, err)
+		}
+	}
+
+	return c, scanner.Err()
+}
+
+func (c *config) set(k, v string) error {
+	var err error
+	switch k {
+	case "paths.movie":
+		c.movieDir, err = tomlString(v)
+	case "paths.show":
+		c.showDir, err = tomlString(v)
+	case "templates.movie", "templates.episode", "templates.movie_dir", "templates.show_dir", "templates.season":
+		var t string
+		if t, err = tomlString(v); err == nil {
+			err = c.templates.Set(strings.ReplaceAll(strings.TrimPrefix(k, "templates."), "_", "-") + "=" + t)
+		}
+	case "owner":
+		c.owner, err = tomlID(v)
+	case "group":
+		c.group, err = tomlID(v)
+	case "change_mode":
+		c.changeMode, err = tomlBool(v)
+	case "change_owner":
+		c.changeOwner, err = tomlBool(v)
+	case "file_mode":
+		c.fileMode, err = tomlMode(v)
+	case "dir_mode":
+		c.dirMode, err = tomlMode(v)
+	case "junk":
+		c.junk, err = tomlStrings(v)
+	case "profile":
+		c.profile, err = tomlString(v)
+		if _, ok := plexize.Profiles[c.profile]; err == nil && !ok {
+			err = fmt.Errorf("unknown profile %s", c.profile)
+		}
+	case "transfer":
+		var t transferFlag
+		if c.transfer, err = tomlString(v); err == nil {
+			err = t.Set(c.transfer)
+		}
+	default:
+		return fmt.Errorf("unknown key")
+	}
+	return err
+}
+
+// stripComment strips a # comment which is not in a string.
+func stripComment(l string) string {
+	for i := 0; i < len(l); i++ {
+		switch l[i] {
+		case '"', '\'':
+			i = quoted(l[i:]) + i
+		case '#':
+			return l[:i]
+		}
+	}
+	return l
+}
+
+// quoted returns the index of the closing quote of a string starting with a
+// quote, or the length of the string if it is not closed.
+func quoted(v string) int {
+	for i := 1; i < len(v); i++ {
+		switch {
+		case v[0] == '"' && v[i] == '\\':
+			i++
+		case v[i] == v[0]:
+			return i
+		}
+	}
+	return len(v)
+}
+
+func tomlString(v string) (string, error) {
+	switch {
+	case len(v) >= 2 && v[0] == '\'' && v[len(v)-1] == '\'':
+		return v[1 : len(v)-1], nil
+	case len(v) >= 2 && v[0] == '"':
+		return strconv.Unquote(v)
+	}
+	return "", fmt.Errorf("%s is not a string", v)
+}
+
+func tomlBool(v string) (*bool, error) {
+	b, err := strconv.ParseBool(v)
+	if err != nil || v != "true" && v != "false" {
+		return nil, fmt.Errorf("%s is not a boolean", v)
+	}
+	return &b, nil
+}
+
+// tomlID parses a user or group name, or a numeric ID, like "plex" or 1000.
+func tomlID(v string) (string, error) {
+	if _, err := strconv.ParseUint(v, 10, 32); err == nil {
+		return v, nil
+	}
+	return tomlString(v)
+}
+
+// tomlMode parses a file mode, like "0660" or 0o660.
+func tomlMode(v string) (os.FileMode, error) {
+	if s, err := tomlString(v); err == nil {
+		v = s
+	}
+	return parseMode(v)
+}
+
+// tomlStrings parses a single line array of strings.
+func tomlStrings(v string) ([]string, error) {
+	if !strings.HasPrefix(v, "[") || !strings.HasSuffix(v, "]") {
+		return nil, fmt.Errorf("%s is not an array", v)
+	}
+	v = strings.TrimSpace(v[1 : len(v)-1])
+	var ss []string
+	for v != "" {
+		end := 0
+		if v[0] == '"' || v[0] == '\'' {
+			end = quoted(v)
+		}
+		if end == 0 || end >= len(v) {
+			return nil, fmt.Errorf("%s is not an array of strings", v)
+		}
+		s, err := tomlString(v[:end+1])
+		if err != nil {
+			return nil, err
+		}
+		ss = append(ss, s)
+		v = strings.TrimSpace(v[end+1:])
+		if v != "" && v[0] != ',' {
+			return nil, fmt.Errorf("%s is not an array of strings", v)
+		}
+		v = strings.TrimSpace(strings.TrimPrefix(v, ","))
+	}
+	return ss, nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/m4ns0ur/plexize"
+)
+
+func TestParseConfig(t *testing.T) {
+	const c = `# plexize config
+owner = 1000   # user
+group = 'video'
+change_mode = true
+change_owner = false
+file_mode = "0664"
+dir_mode = "2775"
+junk = ["www.site.org", "[#site]", 'C:\x']
+profile = "jellyfin"
+transfer = "hardlink"
+
+[paths]
+movie = "/srv/media/movies"
+show = "/srv/media/tv \"shows\""
+
+[templates]
+season = "Season {{num .Season}}"
+movie_dir = '{{.TitleYear}}'
+`
+
+	cfg, err := parseConfig(strings.NewReader(c))
+	if err != nil {
+		t.Fatalf("Cannot parse config: %v\n", err)
+	}
+
+	yes, no := true, false
+	want := config{
+		movieDir:    "/srv/media/movies",
+		showDir:     `/srv/media/tv "shows"`,
+		owner:       "1000",
+		group:       "video",
+		changeMode:  &yes,
+		changeOwner: &no,
+		fileMode:    0664,
+		dirMode:     os.ModeSetgid | 0775,
+		junk:        []string{"www.site.org", "[#site]", `C:\x`},
+		profile:     "jellyfin",
+		transfer:    "hardlink",
+		templates:   templatesFlag{MovieDir: "{{.TitleYear}}", Season: "Season {{num .Season}}"},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("config:\ngot:  %+v\nwant: %+v", cfg, want)
+	}
+
+	for _, c := range []string{
+		"owner = plex",
+		"group = -1",
+		"file_mode = 0o1777777",
+		"change_mode = yes",
+		"junk = [\"a\", b]",
+		"junk = ['a' 'b']",
+		"profile = \"foo\"",
+		"transfer = \"teleport\"",
+		"[foo]",
+		"unknown = 1",
+		"owner",
+	} {
+		if _, err := parseConfig(strings.NewReader(c)); err == nil {
+			t.Errorf("config: %s\ngot:  nil\nwant: error", c)
+		}
+	}
+
+	if m, err := tomlMode("0o2775"); err != nil || m != os.ModeSetgid|0775 {
+		t.Errorf("mode: %s\ngot:  %v %v\nwant: %v", "0o2775", m, err, os.ModeSetgid|0775)
+	}
+}
+
+func TestOut(t *testing.T) {
+	ts := []struct {
+		o options
+		m plexize.Media
+		p string
+	}{
+		{options{movieOutDir: "movies", showOutDir: "tv"}, plexize.Media{Title: "Foo"}, "movies"},
+		{options{movieOutDir: "movies", showOutDir: "tv"}, plexize.Media{Title: "Foo", Season: "01", Episode: "02"}, "tv"},
+		{options{outDir: "target", movieOutDir: "movies", showOutDir: "tv"}, plexize.Media{Title: "Foo", Season: "01", Episode: "02"}, "target"},
+		{options{}, plexize.Media{Title: "Foo"}, ""},
+	}
+
+	for _, tt := range ts {
+		if p := tt.o.out(tt.m); p != tt.p {
+			t.Errorf("out: %+v\ngot:  %s\nwant: %s", tt.m, p, tt.p)
+		}
+	}
+}
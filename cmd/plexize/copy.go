@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// rename renames a file, or copies and then removes it if the new path is on
+// another device.
+func rename(path, newPath string) error {
+	err := os.Rename(path, newPath)
+	if err == nil || !crossDevice(err) {
+		return err
+	}
+
+	log.Printf("%s is on another device, copying %s\n", newPath, path)
+	if err := copyFile(path, newPath); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// copyFile copies a file with its mode and modification time, and verifies
+// size and checksum of the copy. The copy is written in a temporary file next
+// to the new path, and renamed after the verification.
+func copyFile(path, newPath string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	fi, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	tmp := partPath(newPath)
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_EXCL|os.O_WRONLY, fi.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	ok := false
+	defer func() {
+		if !ok {
+			dst.Close()
+			os.Remove(tmp)
+		}
+	}()
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(dst, h), src); err != nil {
+		return err
+	}
+	if err := dst.Sync(); err != nil {
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+
+	if err := verify(tmp, fi.Size(), h.Sum(nil)); err != nil {
+		return err
+	}
+	if err := os.Chtimes(tmp, fi.ModTime(), fi.ModTime()); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, newPath); err != nil {
+		return err
+	}
+	ok = true
+	return nil
+}
+
+// partPath returns the temporary path of a file being put in a new path.
+func partPath(newPath string) string {
+	return filepath.Join(filepath.Dir(newPath), "."+filepath.Base(newPath)+".part")
+}
+
+// verify verifies size and SHA-256 checksum of a file.
+func verify(path string, size int64, sum []byte) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return err
+	}
+	if n != size {
+		return fmt.Errorf("size of the copy %s is %d, want %d", path, n, size)
+	}
+	if !bytes.Equal(h.Sum(nil), sum) {
+		return fmt.Errorf("checksum of the copy %s does not match", path)
+	}
+	return nil
+}
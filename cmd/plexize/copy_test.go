@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCopyFile(t *testing.T) {
+	d, err := testDir()
+	if err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	defer os.RemoveAll(d)
+
+	p, np := filepath.Join(d, "Movie.2019.mkv"), filepath.Join(d, "Movie (2019).mkv")
+	if err := os.WriteFile(p, []byte("movie"), 0640); err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	mt := time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := os.Chtimes(p, mt, mt); err != nil {
+		t.Fatalf("Cannot change times of temp file: %v\n", err)
+	}
+
+	if err := copyFile(p, np); err != nil {
+		t.Fatalf("copy: %s\ngot:  %v\nwant: nil", p, err)
+	}
+
+	if b, err := os.ReadFile(np); err != nil || string(b) != "movie" {
+		t.Errorf("content: %s\ngot:  %q %v\nwant: %q", np, b, err, "movie")
+	}
+	if fi, err := os.Stat(np); err == nil && (!fi.ModTime().Equal(mt) || fi.Mode().Perm() != 0640) {
+		t.Errorf("stat: %s\ngot:  %v %v\nwant: %v %v", np, fi.ModTime(), fi.Mode().Perm(), mt, os.FileMode(0640))
+	}
+	if _, err := os.Stat(filepath.Join(d, ".Movie (2019).mkv.part")); !os.IsNotExist(err) {
+		t.Errorf("temp file exists: %v\n", err)
+	}
+	if err := verify(np, 6, nil); err == nil {
+		t.Errorf("verify: %s\ngot:  nil\nwant: error", np)
+	}
+}
+
+func TestRenameCrossDevice(t *testing.T) {
+	const shm = "/dev/shm"
+
+	d, err := testDir()
+	if err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	defer os.RemoveAll(d)
+	sd, err := os.MkdirTemp(shm, "plexize")
+	if err != nil {
+		t.Skipf("no %s: %v\n", shm, err)
+	}
+	defer os.RemoveAll(sd)
+
+	p, np := filepath.Join(sd, "Movie.2019.mkv"), filepath.Join(d, "Movie (2019).mkv")
+	if err := os.WriteFile(p, []byte("movie"), 0666); err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	if err := os.Link(p, filepath.Join(d, "link")); err == nil {
+		t.Skipf("%s and %s are on the same device\n", sd, d)
+	}
+
+	if err := rename(p, np); err != nil {
+		t.Fatalf("rename: %s\ngot:  %v\nwant: nil", p, err)
+	}
+	if b, err := os.ReadFile(np); err != nil || string(b) != "movie" {
+		t.Errorf("content: %s\ngot:  %q %v\nwant: %q", np, b, err, "movie")
+	}
+	if _, err := os.Stat(p); !os.IsNotExist(err) {
+		t.Errorf("file exists: %s\n", p)
+	}
+
+	if m, err := transfer(np, p, transferHardlink); err != nil || m != transferCopy {
+		t.Errorf("transfer: %s\ngot:  %s %v\nwant: %s nil", np, m, err, transferCopy)
+	}
+	if b, err := os.ReadFile(p); err != nil || string(b) != "movie" {
+		t.Errorf("content: %s\ngot:  %q %v\nwant: %q", p, b, err, "movie")
+	}
+}
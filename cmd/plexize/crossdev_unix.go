@@ -0,0 +1,14 @@
+//go:build !windows && !plan9
+
+package main
+
+import (
+	"errors"
+	"syscall"
+)
+
+// crossDevice reports whether a rename error is for a new path on another
+// device.
+func crossDevice(err error) bool {
+	return errors.Is(err, syscall.EXDEV)
+}
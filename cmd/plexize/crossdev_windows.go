@@ -0,0 +1,15 @@
+package main
+
+import (
+	"errors"
+	"syscall"
+)
+
+// errorNotSameDevice is ERROR_NOT_SAME_DEVICE of Windows.
+const errorNotSameDevice = syscall.Errno(17)
+
+// crossDevice reports whether a rename error is for a new path on another
+// drive.
+func crossDevice(err error) bool {
+	return errors.Is(err, errorNotSameDevice)
+}
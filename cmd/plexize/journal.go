@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	journalExt = ".jsonl"
+	undoneExt  = ".undone"
+)
+
+// journal records every change of a run, to undo the run later. The journal
+// file is created on the first change.
+type journal struct {
+	dir, id string
+
+	mu  sync.Mutex
+	f   *os.File
+	enc *json.Encoder
+}
+
+// entry is a change of a run. Mode, UID and GID are the old mode and owner of
+// a changed file.
+type entry struct {
+	Op   string      `json:"op"` // rename, copy, hardlink, symlink, reflink, mkdir, chmod or chown.
+	Path string      `json:"path"`
+	New  string      `json:"new,omitempty"`
+	Mode fs.FileMode `json:"mode,omitempty"`
+	UID  int         `json:"uid,omitempty"`
+	GID  int         `json:"gid,omitempty"`
+}
+
+func newJournal(dir string) *journal {
+	return &journal{dir: dir, id: time.Now().Format("20060102-150405.000")}
+}
+
+// defaultJournalDir returns the plexize/journal folder of the user config
+// folder.
+func defaultJournalDir() string {
+	d, err := os.UserConfigDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "plexize", "journal")
+	}
+	return filepath.Join(d, "plexize", "journal")
+}
+
+// record writes a change to the journal. Paths are recorded as absolute
+// paths, so the run can be undone from any folder.
+func (j *journal) record(e entry) {
+	if j == nil {
+		return
+	}
+	e.Path, e.New = absPath(e.Path), absPath(e.New)
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.f == nil {
+		if err := os.MkdirAll(j.dir, 0700); err != nil {
+			log.Printf("cannot make the journal folder: %v\n", err)
+			return
+		}
+		f, err := os.OpenFile(filepath.Join(j.dir, j.id+journalExt), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			log.Printf("cannot create the journal: %v\n", err)
+			return
+		}
+		j.f, j.enc = f, json.NewEncoder(f)
+	}
+	if err := j.enc.Encode(e); err != nil {
+		log.Printf("cannot write the journal: %v\n", err)
+	}
+}
+
+// absPath returns the absolute path of p, or p itself if it is empty or the
+// absolute path is unknown.
+func absPath(p string) string {
+	if p == "" {
+		return p
+	}
+	if a, err := filepath.Abs(p); err == nil {
+		return a
+	}
+	return p
+}
+
+// close closes the journal file, and reports the run ID if anything is
+// recorded.
+func (j *journal) close() {
+	if j == nil || j.f == nil {
+		return
+	}
+	if err := j.f.Close(); err != nil {
+		log.Printf("cannot close the journal: %v\n", err)
+	}
+	log.Printf("run %s is recorded, undo it with: plexize undo %s\n", j.id, j.id)
+}
+
+// lastRun returns the ID of the last run which is not undone.
+func lastRun(dir string) (string, error) {
+	es, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	var ids []string
+	for _, e := range es {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), journalExt) {
+			ids = append(ids, strings.TrimSuffix(e.Name(), journalExt))
+		}
+	}
+	if len(ids) == 0 {
+		return "", errors.New("there is no run to undo")
+	}
+	sort.Strings(ids)
+	return ids[len(ids)-1], nil
+}
+
+// undo reverses the changes of a run in reverse order: moves files back,
+// removes copies and links, restores modes and owners, and removes created
+// folders if they are empty.
+func undo(dir, id string, o options) error {
+	p := filepath.Join(dir, id+journalExt)
+	f, err := os.Open(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("run %s is not found, or it is undone already", id)
+		}
+		return err
+	}
+	var es []entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			f.Close()
+			return fmt.Errorf("invalid journal entry: %v", err)
+		}
+		es = append(es, e)
+	}
+	f.Close()
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	failed := false
+	for i := len(es) - 1; i >= 0; i-- {
+		e := es[i]
+		switch e.Op {
+		case "rename":
+			log.Printf("%s -> %s\n", e.New, e.Path)
+			if o.dryRun {
+				continue
+			}
+			if _, err := os.Stat(e.Path); err == nil {
+				log.Printf("cannot move back %s, %s exists\n", e.New, e.Path)
+				failed = true
+				continue
+			}
+			if err := rename(e.New, e.Path); err != nil {
+				log.Printf("cannot move back the file: %v\n", err)
+				failed = true
+			}
+		case transferCopy, transferHardlink, transferSymlink, transferReflink:
+			log.Printf("remove %s\n", e.New)
+			if o.dryRun {
+				continue
+			}
+			if err := os.Remove(e.New); err != nil && !os.IsNotExist(err) {
+				log.Printf("cannot remove the file: %v\n", err)
+				failed = true
+			}
+		case "mkdir":
+			log.Printf("remove %s\n", e.Path)
+			if o.dryRun {
+				continue
+			}
+			// Folders with other files are kept.
+			if err := os.Remove(e.Path); err != nil && !os.IsNotExist(err) {
+				log.Printf("cannot remove the folder: %v\n", err)
+			}
+		case "chmod":
+			log.Printf("chmod %v %s\n", e.Mode, e.Path)
+			if o.dryRun {
+				continue
+			}
+			if err := os.Chmod(e.Path, e.Mode); err != nil && !os.IsNotExist(err) {
+				log.Printf("cannot change the file mode: %v\n", err)
+				failed = true
+			}
+		case "chown":
+			log.Printf("chown %d:%d %s\n", e.UID, e.GID, e.Path)
+			if o.dryRun {
+				continue
+			}
+			if err := os.Lchown(e.Path, e.UID, e.GID); err != nil && !os.IsNotExist(err) {
+				log.Printf("cannot change owner of the file: %v\n", err)
+				failed = true
+			}
+		default:
+			return fmt.Errorf("unknown journal operation: %s", e.Op)
+		}
+	}
+
+	if o.dryRun {
+		return nil
+	}
+	if failed {
+		return fmt.Errorf("run %s is undone partially", id)
+	}
+	return os.Rename(p, filepath.Join(dir, id+undoneExt))
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/m4ns0ur/plexize"
+)
+
+func TestUndo(t *testing.T) {
+	ps := []string{"Movie.2019.1080p.mkv", "Movie.2019.1080p.en.srt", "foo.s01e02.bar.mkv"}
+
+	d, err := testDir(ps...)
+	if err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	defer os.RemoveAll(d)
+	if err := os.Chmod(filepath.Join(d, ps[0]), 0644); err != nil {
+		t.Fatalf("Cannot change mode of temp file: %v\n", err)
+	}
+
+	jd := filepath.Join(d, "journal")
+	o := options{separate: true, chmod: true, outDir: filepath.Join(d, "target"), journal: newJournal(jd)}
+	for _, p := range ps {
+		if filepath.Ext(p) == ".mkv" {
+			process(filepath.Join(d, p), plexize.Media{}, o)
+		}
+	}
+	o.journal.close()
+
+	if _, err := os.Stat(filepath.Join(d, "target", "Movie (2019)", "Movie (2019).en.srt")); err != nil {
+		t.Fatalf("file does not exist:  %v\n", err)
+	}
+
+	id, err := lastRun(jd)
+	if err != nil || id != o.journal.id {
+		t.Fatalf("last run: %s\ngot:  %s %v\nwant: %s", jd, id, err, o.journal.id)
+	}
+	if err := undo(jd, id, options{}); err != nil {
+		t.Fatalf("undo: %s\ngot:  %v\nwant: nil", id, err)
+	}
+
+	for _, p := range ps {
+		if _, err := os.Stat(filepath.Join(d, p)); os.IsNotExist(err) {
+			t.Errorf("file does not exist:  %v\n", err)
+		}
+	}
+	if fi, err := os.Stat(filepath.Join(d, ps[0])); err == nil && fi.Mode().Perm() != 0644 {
+		t.Errorf("mode: %s\ngot:  %v\nwant: %v", ps[0], fi.Mode().Perm(), os.FileMode(0644))
+	}
+	if _, err := os.Stat(filepath.Join(d, "target")); !os.IsNotExist(err) {
+		t.Errorf("dir exists:  %s\n", filepath.Join(d, "target"))
+	}
+	if _, err := lastRun(jd); err == nil {
+		t.Errorf("last run: %s\ngot:  nil\nwant: error", jd)
+	}
+	if err := undo(jd, id, options{}); err == nil {
+		t.Errorf("undo: %s\ngot:  nil\nwant: error", id)
+	}
+}
+
+func TestUndoReplace(t *testing.T) {
+	const v, old, nw = "Movie.2019.1080p.mkv", "old", "larger new"
+	np := filepath.Join("dst", "Movie (2019).mkv")
+
+	d, err := testDir()
+	if err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	defer os.RemoveAll(d)
+	if err := os.Mkdir(filepath.Join(d, "dst"), 0777); err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	if err := os.WriteFile(filepath.Join(d, v), []byte(nw), 0666); err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	if err := os.WriteFile(filepath.Join(d, np), []byte(old), 0666); err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+
+	jd := filepath.Join(d, "journal")
+	o := options{collision: collisionReplace, outDir: filepath.Join(d, "dst"), journal: newJournal(jd)}
+	process(filepath.Join(d, v), plexize.Media{}, o)
+	o.journal.close()
+
+	if b, err := os.ReadFile(filepath.Join(d, np)); err != nil || string(b) != nw {
+		t.Fatalf("replaced: %s\ngot:  %s %v\nwant: %s", np, b, err, nw)
+	}
+
+	if err := undo(jd, o.journal.id, options{}); err != nil {
+		t.Fatalf("undo: %s\ngot:  %v\nwant: nil", o.journal.id, err)
+	}
+	for p, c := range map[string]string{v: nw, np: old} {
+		if b, err := os.ReadFile(filepath.Join(d, p)); err != nil || string(b) != c {
+			t.Errorf("undone: %s\ngot:  %s %v\nwant: %s", p, b, err, c)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(d, np+replacedExt)); !os.IsNotExist(err) {
+		t.Errorf("file exists:  %s\n", filepath.Join(d, np+replacedExt))
+	}
+}
+
+func TestUndoAfterChdir(t *testing.T) {
+	const v = "Movie.2019.1080p.mkv"
+
+	d, err := testDir(v)
+	if err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	defer os.RemoveAll(d)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Cannot get working directory: %v\n", err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(d); err != nil {
+		t.Fatalf("Cannot change working directory: %v\n", err)
+	}
+
+	jd := filepath.Join(d, "journal")
+	o := options{separate: true, outDir: "target", journal: newJournal(jd)}
+	process(v, plexize.Media{}, o)
+	o.journal.close()
+
+	if _, err := os.Stat(filepath.Join(d, "target", "Movie (2019)", "Movie (2019).mkv")); err != nil {
+		t.Fatalf("file does not exist:  %v\n", err)
+	}
+
+	if err := os.Chdir(wd); err != nil {
+		t.Fatalf("Cannot change working directory: %v\n", err)
+	}
+	if err := undo(jd, o.journal.id, options{}); err != nil {
+		t.Fatalf("undo: %s\ngot:  %v\nwant: nil", o.journal.id, err)
+	}
+	if _, err := os.Stat(filepath.Join(d, v)); err != nil {
+		t.Errorf("file does not exist:  %v\n", err)
+	}
+	if _, err := os.Stat(filepath.Join(d, "target")); !os.IsNotExist(err) {
+		t.Errorf("dir exists:  %s\n", filepath.Join(d, "target"))
+	}
+}
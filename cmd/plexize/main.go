@@ -0,0 +1,964 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"syscall"
+
+	"github.com/m4ns0ur/plexize"
+)
+
+var uid int = -1
+var gid int
+
+func usage() {
+	fmt.Fprintln(flag.CommandLine.Output(), `Movie and TV show files, Plex friendly maker.
+
+Usage:
+  plexize [-]
+  plexize [OPTION]... FILE...
+  plexize watch [OPTION]... DIR...
+  plexize undo [OPTION]... [RUN-ID]
+
+Options:
+  -d, --dry-run             Show result without running
+  -m, --change-mode         Change file mode to 660 and made folder mode to 770
+  -o, --change-owner        Change file and made folder owner to plex:plex (sudo might be needed)
+  -U, --owner USER          Owner of files by name or ID, implies -o (default is plex)
+  -G, --group GROUP         Group of files by name or ID, implies -o (default is the primary group of the owner)
+  -M, --file-mode MODE      File mode, like 0664, implies -m (default is 0660)
+  -D, --dir-mode MODE       Made folder mode, like 2775 with setgid, implies -m (default is 0770)
+  -p, --path PATH           Output path (move file to the path and then refactor)
+  -s, --separate            Separate movie files in their own folders (not required for TV series)
+  -r, --rename DIR          Rename the parsed plex directory (good for TV series)
+  -k, --pack                Treat directories as season packs (folder name is used for all files inside)
+  -l, --specials FILE       Specials list to map special episode numbers (show | special | episode per line)
+  -g, --languages           Add audio and subtitle languages to the file name, like [fr][sub-en]
+  -i, --id ID               External ID of a single file (tt1234567, imdb-tt1234567, tmdb-123 or tvdb-123)
+  -x, --extras-suffix       Put movie extras next to the movie with a suffix, like Trailer-trailer.mkv, instead of extras folders
+  -R, --recursive           Walk directories and convert media files inside, skipping samples and junk
+  -n, --include GLOB        Convert only files matching the glob in recursive mode (can be repeated)
+  -e, --exclude GLOB        Skip files matching the glob in recursive mode (can be repeated)
+  -c, --collision POLICY    What to do if a file exists: skip, suffix (keep both), replace (if larger) or prompt (default is skip)
+  -P, --profile NAME        Naming profile of a media server: plex, jellyfin, emby or kodi (default is plex)
+  -T, --template TYPE=TMPL  Naming template of movie, episode, movie-dir, show-dir or season in Go text/template syntax (can be repeated)
+  -t, --transfer MODE       How to put files in place: move, copy, hardlink, symlink or reflink (default is move)
+  -j, --journal DIR         Journal folder of runs to undo (default is plexize/journal in the user config folder)
+  -C, --config FILE         Config file, flags override its values (default is plexize/config.toml in the user config folder)
+  -a, --artwork             Move artwork images, like cover.jpg or season01-poster.jpg, as poster.jpg, fanart.jpg or Season01.jpg
+
+Example:
+  $ plexize                                        # start in interactive mode to convert file(s) name
+  $ cat movie_list.txt | plexize                   # convert file(s) name with piping
+  $ plexize trainwreck.mkv war.dogs.2016.mkv       # convert multiple files
+  $ plexize the*.mkv                               # convert multiple files with wildcard
+  $ plexize -d The.Platform.2019.720p.mkv          # dry run
+  $ plexize -p ~/plex The.Platform.2019.720p.mkv   # move the file to ~/plex and convert
+  $ plexize -m -o -s The.Platform.2019.720p.mkv    # change mode/owner and move the movie file to its own folder
+  $ plexize -m -o The.Flash.2014.S01E01.HDTV.mkv   # change mode/owner a TV show file (would be separated in its own folder)
+  $ plexize -m -o -r dc-flash The.Flash.S01E01.mkv # change mode/owner and rename the TV show folder
+  $ plexize -k Breaking.Bad.S03.1080p.BluRay/      # convert a season pack using its folder name
+  $ plexize -l specials.txt Doctor.Who.Special.*   # convert specials with episode numbers from a list
+  $ plexize -s -i tt0372784 Batman.Begins.2005.mkv # convert a movie with its IMDb ID in the folder name
+  $ plexize -k Movie.2019.1080p.BluRay/            # convert a movie folder with its trailers, featurettes, etc.
+  $ plexize -a -k Breaking.Bad.S03.1080p.BluRay/   # convert a season pack with its posters and fanarts
+  $ plexize -R -e '*.avi' ~/Downloads              # convert all media files in a tree, except avi files
+  $ plexize watch -m -o -p ~/plex ~/Downloads      # convert finished downloads into ~/plex until interrupted
+  $ plexize -c suffix -p ~/plex *.mkv              # convert and keep both files if a file exists
+  $ plexize -t hardlink -R -p ~/plex ~/torrents    # convert hardlinks of files, keeping torrents seeding
+  $ plexize -T 'episode={{.Title}} - {{upper .EpisodeTag}}' -T 'season=Season {{num .Season}}' Show.S01E02.mkv
+                                                   # convert with custom naming templates
+  $ plexize -P jellyfin -s Batman.Begins.2005.mkv  # convert a movie for Jellyfin
+  $ plexize -U 1000 -G media -M 0664 -D 2775 *.mkv # convert with the owner, group and modes of a shared library
+  $ plexize undo                                   # undo the last run, moving files back`)
+}
+
+type options struct {
+	dryRun, chmod, chown, separate, pack bool
+	languages, extrasSuffix, artwork     bool
+	recursive                            bool
+	outDir, renameDir, id                string
+	movieOutDir, showOutDir              string
+	fileMode, dirMode                    os.FileMode
+	include, exclude                     globs
+	collision                            collisionFlag
+	transfer                             transferFlag
+	batch                                batch
+	namer                                *plexize.Namer
+	parser                               *plexize.Parser
+	journal                              *journal
+	specials                             plexize.Specials
+}
+
+// out returns the output path of a media, the path flag or the path of the
+// media type in the config file.
+func (o options) out(m plexize.Media) string {
+	switch {
+	case o.outDir != "":
+		return o.outDir
+	case m.IsEpisode():
+		return o.showOutDir
+	}
+	return o.movieOutDir
+}
+
+// mode returns the mode of files, 0660 by default.
+func (o options) mode() os.FileMode {
+	if o.fileMode == 0 {
+		return 0660
+	}
+	return o.fileMode
+}
+
+// dirModeOrDefault returns the mode of made folders, 0770 by default.
+func (o options) dirModeOrDefault() os.FileMode {
+	if o.dirMode == 0 {
+		return 0770
+	}
+	return o.dirMode
+}
+
+var videoExts = map[string]bool{
+	".avi": true, ".divx": true, ".flv": true, ".m2ts": true, ".m4v": true, ".mkv": true, ".mov": true,
+	".mp4": true, ".mpeg": true, ".mpg": true, ".ogm": true, ".ts": true, ".webm": true, ".wmv": true,
+}
+
+func main() {
+	log.SetFlags(0)
+
+	var (
+		o            options
+		specialsFile string
+		journalDir   string
+		templates    templatesFlag
+		profile      string
+		configFile   string
+		owner, group string
+		fileMode     modeFlag
+		dirMode      modeFlag
+	)
+
+	flag.Usage = usage
+	flag.BoolVar(&o.dryRun, "d", false, "Show result without running")
+	flag.BoolVar(&o.dryRun, "dry-run", false, "Show result without running")
+	flag.BoolVar(&o.chmod, "m", false, "Change file mode to 660")
+	flag.BoolVar(&o.chmod, "change-mode", false, "Change file mode to 660")
+	flag.BoolVar(&o.chown, "o", false, "Change file owner (default is plex:plex)")
+	flag.BoolVar(&o.chown, "change-owner", false, "Change file owner (default is plex:plex)")
+	flag.StringVar(&owner, "U", "", "Owner of files by name or ID")
+	flag.StringVar(&owner, "owner", "", "Owner of files by name or ID")
+	flag.StringVar(&group, "G", "", "Group of files by name or ID")
+	flag.StringVar(&group, "group", "", "Group of files by name or ID")
+	flag.Var(&fileMode, "M", "File mode")
+	flag.Var(&fileMode, "file-mode", "File mode")
+	flag.Var(&dirMode, "D", "Made folder mode")
+	flag.Var(&dirMode, "dir-mode", "Made folder mode")
+	flag.StringVar(&o.outDir, "p", "", "Output path (move file to the path and then refactor)")
+	flag.StringVar(&o.outDir, "path", "", "Output path (move file to the path and then refactor)")
+	flag.BoolVar(&o.separate, "s", false, "Separate movie files in their own folders (not required for TV series)")
+	flag.BoolVar(&o.separate, "separate", false, "Separate movie files in their own folders (not required for TV series)")
+	flag.StringVar(&o.renameDir, "r", "", "Rename the parsed plex directory (good for TV series)")
+	flag.StringVar(&o.renameDir, "rename", "", "Rename the parsed plex directory (good for TV series)")
+	flag.BoolVar(&o.pack, "k", false, "Treat directories as season packs (folder name is used for all files inside)")
+	flag.BoolVar(&o.pack, "pack", false, "Treat directories as season packs (folder name is used for all files inside)")
+	flag.StringVar(&specialsFile, "l", "", "Specials list to map special episode numbers")
+	flag.StringVar(&specialsFile, "specials", "", "Specials list to map special episode numbers")
+	flag.BoolVar(&o.languages, "g", false, "Add audio and subtitle languages to the file name")
+	flag.BoolVar(&o.languages, "languages", false, "Add audio and subtitle languages to the file name")
+	flag.StringVar(&o.id, "i", "", "External ID of a single file")
+	flag.StringVar(&o.id, "id", "", "External ID of a single file")
+	flag.BoolVar(&o.extrasSuffix, "x", false, "Put movie extras next to the movie with a suffix")
+	flag.BoolVar(&o.extrasSuffix, "extras-suffix", false, "Put movie extras next to the movie with a suffix")
+	flag.BoolVar(&o.recursive, "R", false, "Walk directories and convert media files inside")
+	flag.BoolVar(&o.recursive, "recursive", false, "Walk directories and convert media files inside")
+	flag.Var(&o.include, "n", "Convert only files matching the glob in recursive mode")
+	flag.Var(&o.include, "include", "Convert only files matching the glob in recursive mode")
+	flag.Var(&o.exclude, "e", "Skip files matching the glob in recursive mode")
+	flag.Var(&o.exclude, "exclude", "Skip files matching the glob in recursive mode")
+	flag.StringVar(&journalDir, "j", defaultJournalDir(), "Journal folder of runs to undo")
+	flag.StringVar(&journalDir, "journal", defaultJournalDir(), "Journal folder of runs to undo")
+	o.collision = collisionSkip
+	flag.Var(&o.collision, "c", "What to do if a file exists: skip, suffix, replace or prompt")
+	flag.Var(&o.collision, "collision", "What to do if a file exists: skip, suffix, replace or prompt")
+	flag.StringVar(&profile, "P", "plex", "Naming profile of a media server: plex, jellyfin, emby or kodi")
+	flag.StringVar(&profile, "profile", "plex", "Naming profile of a media server: plex, jellyfin, emby or kodi")
+	flag.Var(&templates, "T", "Naming template of a media type: movie, episode, movie-dir, show-dir or season")
+	flag.Var(&templates, "template", "Naming template of a media type: movie, episode, movie-dir, show-dir or season")
+	o.transfer = transferMove
+	flag.Var(&o.transfer, "t", "How to put files in place: move, copy, hardlink, symlink or reflink")
+	flag.Var(&o.transfer, "transfer", "How to put files in place: move, copy, hardlink, symlink or reflink")
+	flag.StringVar(&configFile, "C", "", "Config file")
+	flag.StringVar(&configFile, "config", "", "Config file")
+	flag.BoolVar(&o.artwork, "a", false, "Move artwork images as Plex local assets")
+	flag.BoolVar(&o.artwork, "artwork", false, "Move artwork images as Plex local assets")
+	args := os.Args[1:]
+	var cmd string
+	if len(args) > 0 && (args[0] == "watch" || args[0] == "undo") {
+		cmd, args = args[0], args[1:]
+	}
+	watchMode := cmd == "watch"
+	flag.CommandLine.Parse(args)
+
+	if cmd == "undo" {
+		if flag.NArg() > 1 {
+			log.Fatalln("only one run can be undone")
+		}
+		id := flag.Arg(0)
+		if id == "" {
+			var err error
+			if id, err = lastRun(journalDir); err != nil {
+				log.Fatalf("cannot find the last run: %v\n", err)
+			}
+		}
+		if o.dryRun {
+			log.Println("Dry run...")
+		}
+		log.Printf("undoing run %s\n", id)
+		if err := undo(journalDir, id, o); err != nil {
+			log.Fatalf("cannot undo the run: %v\n", err)
+		}
+		return
+	}
+
+	cfg, err := loadConfig(configFile, true)
+	if configFile == "" {
+		cfg, err = loadConfig(defaultConfigFile(), false)
+	}
+	if err != nil {
+		log.Fatalf("cannot load the config file: %v\n", err)
+	}
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	o.movieOutDir, o.showOutDir = cfg.movieDir, cfg.showDir
+	if cfg.changeMode != nil && !set["m"] && !set["change-mode"] {
+		o.chmod = *cfg.changeMode
+	}
+	if cfg.changeOwner != nil && !set["o"] && !set["change-owner"] {
+		o.chown = *cfg.changeOwner
+	}
+	o.fileMode, o.dirMode = cfg.fileMode, cfg.dirMode
+	if fileMode != 0 || dirMode != 0 {
+		o.chmod = true
+	}
+	if fileMode != 0 {
+		o.fileMode = os.FileMode(fileMode)
+	}
+	if dirMode != 0 {
+		o.dirMode = os.FileMode(dirMode)
+	}
+	o.parser = plexize.NewParser(cfg.junk)
+	if cfg.profile != "" && !set["P"] && !set["profile"] {
+		profile = cfg.profile
+	}
+	if cfg.transfer != "" && !set["t"] && !set["transfer"] {
+		o.transfer = transferFlag(cfg.transfer)
+	}
+	explicitOwner := owner != "" || group != ""
+	if explicitOwner {
+		o.chown = true
+	}
+	if owner == "" {
+		owner = cfg.owner
+	}
+	if owner == "" {
+		owner = "plex"
+	}
+	if group == "" {
+		group = cfg.group
+	}
+	var ownerErr error
+	if uid, gid, ownerErr = lookupOwner(owner, group); ownerErr != nil && explicitOwner {
+		log.Fatalf("cannot find the owner: %v\n", ownerErr)
+	}
+
+	t, ok := plexize.Profiles[profile]
+	if !ok {
+		log.Fatalf("unknown profile %s (plex, jellyfin, emby or kodi)\n", profile)
+	}
+	if o.namer, err = plexize.NewNamer(templates.over(cfg.templates.over(t))); err != nil {
+		log.Fatalf("invalid naming template: %v\n", err)
+	}
+
+	if specialsFile != "" {
+		f, err := os.Open(specialsFile)
+		if err != nil {
+			log.Fatalf("cannot open the specials list: %v\n", err)
+		}
+		o.specials, err = plexize.LoadSpecials(f)
+		f.Close()
+		if err != nil {
+			log.Fatalf("cannot load the specials list: %v\n", err)
+		}
+	}
+
+	if watchMode && flag.NArg() == 0 {
+		log.Fatalln("no directory to watch")
+	}
+
+	if !watchMode && (flag.Arg(0) == "" || flag.Arg(0) == "-") {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			l := scanner.Text()
+			np, err := convert(l, plexize.Media{}, options{dryRun: true, namer: o.namer, parser: o.parser})
+			if err != nil {
+				log.Printf("cannot convert %s: %v\n", l, err)
+				continue
+			}
+			log.Printf("%s\n", np)
+		}
+		if err := scanner.Err(); err != nil {
+			log.Fatalf("cannot read from stdin: %v\n", err)
+		}
+		os.Exit(0)
+	}
+
+	if o.id != "" {
+		if watchMode || flag.NArg() > 1 || strings.Contains(flag.Arg(0), "*") {
+			log.Fatalln("external ID can be set for a single file only")
+		}
+		// A folder, with -R or -k, would set the ID of every file in it.
+		if fi, err := os.Stat(flag.Arg(0)); err == nil && fi.IsDir() {
+			log.Fatalln("external ID can be set for a single file only, not a folder")
+		}
+		if err := new(plexize.Media).SetID(o.id); err != nil {
+			log.Fatalf("cannot set the external ID: %v\n", err)
+		}
+	}
+
+	if o.dryRun {
+		log.Println("Dry run...")
+	}
+
+	if o.chown {
+		if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+			o.chown = false
+			log.Println("the OS does not support changing the file owner")
+		} else if ownerErr != nil {
+			o.chown = false
+			log.Printf("cannot change the file owner: %v\n", ownerErr)
+		}
+	}
+
+	o.batch = make(batch)
+	if !o.dryRun {
+		o.journal = newJournal(journalDir)
+		defer o.journal.close()
+	}
+
+	if watchMode {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if err := watch(ctx, flag.Args(), o); err != nil {
+			o.journal.close()
+			log.Fatalf("cannot watch: %v\n", err)
+		}
+		return
+	}
+
+	var files []string
+	for i := 0; i < flag.NArg(); i++ {
+		var paths []string
+		var err error
+		if strings.Contains(flag.Arg(i), "*") {
+			paths, err = filepath.Glob(flag.Arg(i))
+			if err != nil {
+				log.Fatalf("invalid path format: %v\n", err)
+			}
+		} else {
+			paths = append(paths, flag.Arg(i))
+		}
+		for _, path := range paths {
+			if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+				if o.pack {
+					processPack(path, o)
+					continue
+				}
+				if o.recursive {
+					processFiles(walk(path, o), true, o)
+					continue
+				}
+			}
+			files = append(files, path)
+		}
+	}
+	processFiles(files, false, o)
+}
+
+// processFiles processes video files, and returns their new paths. Extras are
+// processed last, to put them in the folder of their movie. If the files are
+// from release folders, an extra without its movie in the files is put in the
+// folder of the movie of its release folder.
+func processFiles(files []string, releases bool, o options) []string {
+	var nps, extras []string
+	// The first video of each folder, to put its extras in its new folder.
+	movies := make(map[string][2]string)
+	// Videos alone in their folder, which own the bare artworks of the folder,
+	// found before any video is moved.
+	alone := make(map[string]bool)
+	if o.artwork {
+		for _, path := range files {
+			if vp, ok := releaseVideo(filepath.Dir(path), o); ok && vp == filepath.Clean(path) {
+				alone[path] = true
+			}
+		}
+	}
+	var videos [][2]string
+	for _, path := range files {
+		if _, ok := o.parser.ParseExtra(path); ok {
+			extras = append(extras, path)
+			continue
+		}
+		np := process(path, plexize.Media{}, o)
+		if np == "" {
+			continue
+		}
+		nps = append(nps, np)
+		videos = append(videos, [2]string{path, np})
+		if _, ok := movies[filepath.Dir(path)]; !ok {
+			movies[filepath.Dir(path)] = [2]string{path, np}
+		}
+	}
+
+	for _, path := range extras {
+		e, _ := o.parser.ParseExtra(path)
+		mp, ok := movies[extraSource(path)]
+		md := filepath.Dir(mp[1])
+		if !ok && releases {
+			md, ok = movieDir(extraSource(path), o)
+		}
+		if !ok {
+			// Without a movie, it is a movie named like an extra, like The Interview.
+			if np := process(path, plexize.Media{}, o); np != "" {
+				nps = append(nps, np)
+			}
+			continue
+		}
+		if np := processExtra(path, e, md, o); np != "" {
+			nps = append(nps, np)
+		}
+	}
+
+	if o.artwork {
+		for _, v := range videos {
+			processArtwork(v[0], v[1], plexize.Media{}, alone[v[0]], o)
+		}
+	}
+
+	return nps
+}
+
+// processPack processes video files of a season pack directory, using the
+// directory name as context for each file. In a movie folder, extras like
+// Trailer.mkv or Deleted Scenes/ are put in the folder of the movie.
+func processPack(dir string, o options) {
+	pack, err := o.parser.ParseDir(dir)
+	if err != nil {
+		log.Printf("cannot parse the season pack %s: %v\n", dir, err)
+		return
+	}
+
+	es, err := os.ReadDir(dir)
+	if err != nil {
+		log.Printf("cannot read the season pack: %v\n", err)
+		return
+	}
+	var extras []string
+	for _, e := range es {
+		if e.IsDir() && pack.Season == "" {
+			extras = append(extras, extraFiles(filepath.Join(dir, e.Name()))...)
+		}
+		if e.IsDir() || !videoExts[strings.ToLower(filepath.Ext(e.Name()))] {
+			continue
+		}
+		if _, ok := o.parser.ParseExtra(e.Name()); ok && pack.Season == "" {
+			extras = append(extras, filepath.Join(dir, e.Name()))
+		}
+	}
+	if len(extras) > 0 {
+		o.separate = true
+	}
+
+	var mp, mnp string
+	for _, e := range es {
+		p := filepath.Join(dir, e.Name())
+		if e.IsDir() || !videoExts[strings.ToLower(filepath.Ext(e.Name()))] || slices.Contains(extras, p) {
+			continue
+		}
+		if np := process(p, pack, o); np != "" && mnp == "" {
+			mp, mnp = p, np
+		}
+	}
+	if o.artwork && mp != "" {
+		processArtwork(mp, mnp, pack, true, o)
+	}
+	if len(extras) == 0 {
+		return
+	}
+	md := filepath.Dir(mnp)
+	if mnp == "" {
+		pd, err := o.namer.Dir(pack)
+		if err != nil {
+			log.Printf("cannot name the movie folder: %v\n", err)
+			return
+		}
+		md = filepath.Join(filepath.Dir(filepath.Clean(dir)), pd)
+		if out := o.out(pack); out != "" {
+			md = filepath.Join(out, pd)
+		}
+	}
+	for _, p := range extras {
+		e, _ := o.parser.ParseExtra(p)
+		processExtra(p, e, md, o)
+	}
+}
+
+// extraFiles returns video files of an extras folder, like Deleted Scenes/.
+func extraFiles(dir string) []string {
+	if !plexize.IsExtraDir(dir) {
+		return nil
+	}
+	es, err := os.ReadDir(dir)
+	if err != nil {
+		log.Printf("cannot read the extras folder: %v\n", err)
+		return nil
+	}
+	var ps []string
+	for _, e := range es {
+		if !e.IsDir() && videoExts[strings.ToLower(filepath.Ext(e.Name()))] {
+			ps = append(ps, filepath.Join(dir, e.Name()))
+		}
+	}
+	return ps
+}
+
+// extraSource returns the folder of the movie of an extra, the parent folder
+// if the extra is in an extras folder.
+func extraSource(path string) string {
+	d := filepath.Dir(path)
+	if plexize.IsExtraDir(d) {
+		return filepath.Dir(d)
+	}
+	return d
+}
+
+// movieDir returns the new folder of the movie of a release folder, the only
+// video file which is not an extra, and reports whether the folder holds a
+// single movie.
+func movieDir(dir string, o options) (string, bool) {
+	mp, ok := releaseVideo(dir, o)
+	if !ok {
+		return "", false
+	}
+	o.dryRun = true
+	np, err := convert(mp, plexize.Media{}, o)
+	if err != nil {
+		return "", false
+	}
+	return filepath.Dir(np), true
+}
+
+// releaseVideo returns the only video file of a folder which is not an extra or
+// a sample, and reports whether the folder holds a single release.
+func releaseVideo(dir string, o options) (string, bool) {
+	es, err := os.ReadDir(dir)
+	if err != nil {
+		return "", false
+	}
+	var mp string
+	for _, e := range es {
+		if e.IsDir() || !videoExts[strings.ToLower(filepath.Ext(e.Name()))] {
+			continue
+		}
+		if _, ok := o.parser.ParseExtra(e.Name()); ok || sampleRe.MatchString(strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))) {
+			continue
+		}
+		if mp != "" {
+			return "", false
+		}
+		mp = filepath.Join(dir, e.Name())
+	}
+	return mp, mp != ""
+}
+
+// processExtra moves and changes mode/owner of a movie extra, into an extras
+// folder of the movie folder, or next to the movie with a suffix, and returns
+// the new path of the extra.
+func processExtra(path string, e plexize.Extra, movieDir string, o options) string {
+	np := filepath.Join(movieDir, e.Type, e.Name())
+	if o.extrasSuffix {
+		np = filepath.Join(movieDir, e.SuffixName())
+	}
+	np, ok := resolve(path, np, o)
+	if !ok {
+		return ""
+	}
+	log.Printf("%s -> %s\n", path, np)
+
+	if o.dryRun {
+		return np
+	}
+
+	makeDir(o, "cannot make movie extras folder: %v\n", filepath.Dir(np))
+	move(path, np, o)
+	return np
+}
+
+// processArtwork moves and changes mode/owner of artwork images next to a
+// video, into the new movie, TV show or season folder of the video. Bare
+// artworks, like poster.jpg, are only taken if the video owns its folder, like
+// in a pack or a single release folder; otherwise only artworks with the stem
+// of the video, like Movie.2019-poster.jpg, are taken.
+func processArtwork(path, newPath string, pack plexize.Media, bare bool, o options) {
+	m, err := o.parser.ParseIn(path, pack)
+	if err != nil {
+		return
+	}
+	if !m.IsEpisode() && !o.separate {
+		log.Printf("artworks of %s need a separate movie folder (use -s)\n", path)
+		return
+	}
+	sd := filepath.Dir(newPath)
+	md := sd
+	if m.Season != "" {
+		md = filepath.Dir(sd)
+	}
+
+	dir := filepath.Dir(path)
+	es, err := os.ReadDir(dir)
+	if err != nil {
+		log.Printf("cannot read the artwork folder: %v\n", err)
+		return
+	}
+	stem := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)) + "-"
+	for _, e := range es {
+		a, ok := plexize.ParseArtwork(path, e.Name())
+		if e.IsDir() || !ok || !bare && !strings.HasPrefix(e.Name(), stem) {
+			continue
+		}
+		// A poster of a season pack is the poster of the season.
+		if a.Kind == "poster" && a.Season == "" && pack.Season != "" {
+			a.Season = pack.Season
+		}
+		np := filepath.Join(md, a.Name())
+		if a.Season != "" {
+			if !m.IsEpisode() {
+				continue
+			}
+			sd, err := o.namer.SeasonDir(plexize.Media{Title: m.Title, Year: m.Year, Season: a.Season})
+			if err != nil {
+				log.Printf("cannot name the season folder: %v\n", err)
+				continue
+			}
+			np = filepath.Join(md, sd, a.Name())
+		}
+		np, ok = resolve(filepath.Join(dir, e.Name()), np, o)
+		if !ok {
+			continue
+		}
+		log.Printf("%s -> %s\n", filepath.Join(dir, e.Name()), np)
+
+		if o.dryRun {
+			continue
+		}
+
+		makeDir(o, "cannot make artwork folder: %v\n", filepath.Dir(np))
+		move(filepath.Join(dir, e.Name()), np, o)
+	}
+}
+
+// process converts, moves and changes mode/owner of a file and its sidecar
+// subtitle files, and returns the new path of the file.
+func process(path string, pack plexize.Media, o options) string {
+	np, err := convert(path, pack, o)
+	if err != nil {
+		log.Printf("cannot convert %s: %v\n", path, err)
+		return ""
+	}
+	np, ok := resolve(path, np, o)
+	if !ok {
+		return ""
+	}
+	var scs [][2]string
+	for _, sc := range sidecars(path, np) {
+		if sc[1], ok = resolve(sc[0], sc[1], o); ok {
+			scs = append(scs, sc)
+		}
+	}
+
+	log.Printf("%s -> %s\n", path, np)
+	for _, sc := range scs {
+		log.Printf("%s -> %s\n", sc[0], sc[1])
+	}
+
+	if o.dryRun {
+		return np
+	}
+
+	// The new folders are made after resolving collisions, not to leave
+	// empty folders of skipped files.
+	if d := filepath.Dir(np); d != filepath.Dir(filepath.Clean(path)) {
+		makeDir(o, "cannot make movie or TV series folder: %v\n", d)
+	}
+	move(path, np, o)
+	for _, sc := range scs {
+		move(sc[0], sc[1], o)
+	}
+
+	// TODO: support fixing title in metadata.
+
+	return np
+}
+
+// sidecars returns sidecar subtitle files of a video, with their new paths
+// next to the new path of the video. Sidecars of another video in the folder
+// with a longer stem, like Alien.Resurrection.en.srt of Alien.Resurrection.mkv
+// for Alien.mkv, are left to that video.
+func sidecars(path, newPath string) [][2]string {
+	dir, _ := filepath.Split(path)
+	rd := dir
+	if rd == "" {
+		rd = "."
+	}
+	es, err := os.ReadDir(rd)
+	if err != nil {
+		return nil
+	}
+
+	_, n := filepath.Split(path)
+	vs := len(strings.TrimSuffix(n, filepath.Ext(n)))
+	var others []string
+	for _, e := range es {
+		if e.IsDir() || e.Name() == n || !videoExts[strings.ToLower(filepath.Ext(e.Name()))] {
+			continue
+		}
+		if len(strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))) > vs {
+			others = append(others, e.Name())
+		}
+	}
+
+	stem := strings.TrimSuffix(newPath, filepath.Ext(newPath))
+	var ps [][2]string
+	for _, e := range es {
+		if e.IsDir() {
+			continue
+		}
+		sc, ok := plexize.ParseSidecar(path, e.Name())
+		if !ok || slices.ContainsFunc(others, func(v string) bool {
+			_, ok := plexize.ParseSidecar(v, e.Name())
+			return ok
+		}) {
+			continue
+		}
+		ps = append(ps, [2]string{filepath.Join(dir, e.Name()), sc.Name(stem)})
+	}
+
+	return ps
+}
+
+// move moves (or copies or links, by the transfer mode) and changes
+// mode/owner of a file, recording the changes in the journal. Symlinks and
+// hardlinks are not changed mode or owner, not to change the original files.
+func move(path, newPath string, o options) {
+	if filepath.Clean(path) == filepath.Clean(newPath) && o.transfer != transferMove && o.transfer != "" {
+		return
+	}
+	if filepath.Clean(path) != filepath.Clean(newPath) && !setAside(path, newPath, o) {
+		return
+	}
+	mode, err := transfer(path, newPath, o.transfer)
+	if err != nil {
+		if os.IsPermission(err) {
+			log.Printf("you don't have permission to %s the file (you can retry with sudo): %v\n", mode, err)
+		} else {
+			log.Printf("cannot %s the file: %v\n", mode, err)
+		}
+		return
+	}
+	if mode == transferMove {
+		o.journal.record(entry{Op: "rename", Path: path, New: newPath})
+	} else {
+		o.journal.record(entry{Op: mode, Path: path, New: newPath})
+	}
+
+	if mode == transferSymlink || mode == transferHardlink {
+		return
+	}
+
+	if o.chmod {
+		chmod(newPath, o.mode(), o)
+	}
+
+	if o.chown {
+		chown(newPath, o)
+	}
+}
+
+// chmod changes mode of a file, recording the old mode in the journal.
+func chmod(path string, mode os.FileMode, o options) {
+	fi, err := os.Stat(path)
+	if err == nil {
+		err = os.Chmod(path, mode)
+	}
+	if err != nil {
+		log.Printf("cannot change the file mode: %v\n", err)
+		return
+	}
+	o.journal.record(entry{Op: "chmod", Path: path, Mode: fi.Mode() & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky)})
+}
+
+// chown changes owner of a file to plex, recording the old owner in the
+// journal.
+func chown(path string, o options) {
+	fi, err := os.Lstat(path)
+	if err != nil {
+		return
+	}
+	err = os.Lchown(path, uid, gid)
+	if os.IsPermission(err) {
+		log.Printf("you don't have permission to change owner of the file (you can retry with sudo): %v\n", err)
+	}
+	if u, g, ok := owner(fi); ok && err == nil {
+		o.journal.record(entry{Op: "chown", Path: path, UID: u, GID: g})
+	}
+}
+
+// convert returns the new path of a file. It makes no folders, they are made
+// by process once the new path is resolved.
+func convert(path string, pack plexize.Media, o options) (newPath string, err error) {
+	dir, _ := filepath.Split(path)
+
+	m, err := o.parser.ParseIn(path, pack)
+	if err != nil {
+		return "", err
+	}
+	m = o.specials.Map(m)
+	if o.id != "" {
+		if err := m.SetID(o.id); err != nil {
+			return "", err
+		}
+	}
+	if m.Season == "00" && m.Episode == "" {
+		log.Printf("unknown special episode number of %s, map it with a specials list\n", path)
+	}
+
+	md, err := o.namer.Dir(m)
+	if err != nil {
+		return "", err
+	}
+	sd, err := o.namer.SeasonDir(m)
+	if err != nil {
+		return "", err
+	}
+	n, err := o.namer.Name(m)
+	if o.languages {
+		n, err = o.namer.NameWithLanguages(m)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	ps := make([]string, 0, 4)
+	ps = append(ps, dir)
+	if out := o.out(m); out != "" {
+		ps[0] = out
+	}
+	if o.separate || m.IsEpisode() {
+		if o.renameDir != "" {
+			ps = append(ps, o.renameDir)
+		} else {
+			ps = append(ps, md)
+		}
+	}
+	if m.Season != "" {
+		ps = append(ps, sd)
+	}
+	ps = append(ps, n)
+	return fmt.Sprintf("%s%s", filepath.Join(ps...), m.Ext), nil
+}
+
+// makeDir makes a folder with its parents, changing mode/owner of every made
+// folder, and recording them in the journal.
+func makeDir(o options, errMsg string, ps ...string) {
+	d := filepath.Join(ps...)
+	var made []string
+	for p := d; ; p = filepath.Dir(p) {
+		if _, err := os.Stat(p); err == nil || filepath.Dir(p) == p {
+			break
+		}
+		made = append(made, p)
+	}
+
+	err := os.MkdirAll(d, os.ModePerm)
+	if err != nil && !os.IsExist(err) {
+		log.Printf(errMsg, err)
+		return
+	}
+	for i := len(made) - 1; i >= 0; i-- {
+		o.journal.record(entry{Op: "mkdir", Path: made[i]})
+		if o.chmod {
+			chmod(made[i], o.dirModeOrDefault(), o)
+		}
+		if o.chown {
+			chown(made[i], o)
+		}
+	}
+	if o.chown && len(made) == 0 {
+		chown(d, o)
+	}
+}
+
+// templatesFlag is a repeatable flag of naming templates, like
+// episode={{.Title}} - {{.EpisodeTag}}.
+type templatesFlag plexize.Templates
+
+func (t *templatesFlag) String() string {
+	return ""
+}
+
+func (t *templatesFlag) Set(s string) error {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok {
+		return errors.New("template should be like TYPE=TEMPLATE")
+	}
+	switch k {
+	case "movie":
+		t.Movie = v
+	case "episode":
+		t.Episode = v
+	case "movie-dir":
+		t.MovieDir = v
+	case "show-dir":
+		t.ShowDir = v
+	case "season":
+		t.Season = v
+	default:
+		return fmt.Errorf("unknown template type %q (movie, episode, movie-dir, show-dir or season)", k)
+	}
+	return nil
+}
+
+// over returns the templates over base templates, like a profile.
+func (t templatesFlag) over(base plexize.Templates) plexize.Templates {
+	for _, p := range []struct{ t, b *string }{
+		{&t.Movie, &base.Movie},
+		{&t.Episode, &base.Episode},
+		{&t.MovieDir, &base.MovieDir},
+		{&t.ShowDir, &base.ShowDir},
+		{&t.Season, &base.Season},
+	} {
+		if *p.t != "" {
+			*p.b = *p.t
+		}
+	}
+	return base
+}
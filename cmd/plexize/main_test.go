@@ -0,0 +1,420 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/m4ns0ur/plexize"
+)
+
+func TestConvert(t *testing.T) {
+	const mn = "foo.2020.abc"
+	const tn = "foo.s01e02.bar.abc"
+	const dn = "foo.2020.10.15.bar.abc"
+	const an = "[Group] foo - 05 [1080p][ABCD1234].abc"
+	const sn = "foo.special.bar.abc"
+	const pn = "foo.2020.cd2.abc"
+	const ln = "foo.2020.FRENCH.abc"
+
+	d, err := testDir(mn, tn)
+	if err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	defer os.RemoveAll(d)
+
+	sp, err := plexize.LoadSpecials(strings.NewReader("foo | bar | 3"))
+	if err != nil {
+		t.Fatalf("Cannot load specials: %v\n", err)
+	}
+
+	ts := []struct {
+		p       string
+		d, s, c bool
+		g       bool
+		o       string
+		r       string
+		i       string
+		n       string
+	}{
+		{
+			mn, true, false, false, false, "", "", "",
+			"Foo (2020).abc",
+		},
+		{
+			mn, true, false, false, false, "target", "", "",
+			filepath.Join("target", "Foo (2020).abc"),
+		},
+		{
+			mn, true, true, false, false, "", "", "",
+			filepath.Join("Foo (2020)", "Foo (2020).abc"),
+		},
+		{
+			mn, true, true, false, false, "target", "", "",
+			filepath.Join("target", "Foo (2020)", "Foo (2020).abc"),
+		},
+		{
+			tn, true, false, false, false, "", "", "",
+			filepath.Join("Foo", "Season 01", "Foo - s01e02 - Bar.abc"),
+		},
+		{
+			tn, true, false, false, false, "target", "", "",
+			filepath.Join("target", "Foo", "Season 01", "Foo - s01e02 - Bar.abc"),
+		},
+		{
+			tn, true, false, false, false, "target", "renamed-foo", "",
+			filepath.Join("target", "renamed-foo", "Season 01", "Foo - s01e02 - Bar.abc"),
+		},
+		{
+			dn, true, false, false, false, "", "", "",
+			filepath.Join("Foo", "Season 2020", "Foo - 2020-10-15 - Bar.abc"),
+		},
+		{
+			an, true, false, false, false, "", "", "",
+			filepath.Join("Foo", "Foo - e05.abc"),
+		},
+		{
+			sn, true, false, false, false, "", "", "",
+			filepath.Join("Foo", "Specials", "Foo - s00e03 - Bar.abc"),
+		},
+		{
+			pn, true, true, false, false, "", "", "",
+			filepath.Join("Foo (2020)", "Foo (2020) - cd2.abc"),
+		},
+		{
+			mn, true, true, false, false, "", "", "tt0372784",
+			filepath.Join("Foo (2020) {imdb-tt0372784}", "Foo (2020).abc"),
+		},
+		{
+			ln, true, false, false, false, "", "", "",
+			"Foo (2020).abc",
+		},
+		{
+			ln, true, false, false, true, "", "", "",
+			"Foo (2020) [fr].abc",
+		},
+		// Not dry run.
+		{
+			mn, false, true, false, false, filepath.Join(d, "target"), "", "",
+			filepath.Join(d, "target", "Foo (2020)", "Foo (2020).abc"),
+		},
+		{
+			tn, false, false, false, false, filepath.Join(d, "target"), "", "",
+			filepath.Join(d, "target", "Foo", "Season 01", "Foo - s01e02 - Bar.abc"),
+		},
+		{
+			tn, false, false, false, false, filepath.Join(d, "target"), "renamed-foo", "",
+			filepath.Join(d, "target", "renamed-foo", "Season 01", "Foo - s01e02 - Bar.abc"),
+		},
+	}
+
+	for _, tt := range ts {
+		np, err := convert(tt.p, plexize.Media{}, options{dryRun: tt.d, separate: tt.s, chown: tt.c, languages: tt.g, outDir: tt.o, renameDir: tt.r, id: tt.i, specials: sp})
+		if err != nil {
+			t.Errorf("cannot convert %s: %v\n", tt.p, err)
+		}
+		if np != tt.n {
+			t.Errorf("got:  %s\nwant: %s", np, tt.n)
+		}
+		if d, _ := filepath.Split(np); !tt.d {
+			if _, err := os.Stat(d); !os.IsNotExist(err) {
+				t.Errorf("dir exists:  %s\n", d)
+			}
+		}
+	}
+}
+
+func TestConvertTemplates(t *testing.T) {
+	var ts templatesFlag
+	for _, s := range []string{"episode={{.Title}} - {{upper .EpisodeTag}}", "season=Season {{num .Season}}"} {
+		if err := ts.Set(s); err != nil {
+			t.Fatalf("Cannot set template %s: %v\n", s, err)
+		}
+	}
+	if err := ts.Set("show={{.Title}}"); err == nil {
+		t.Errorf("template: %s\ngot:  nil\nwant: error", "show={{.Title}}")
+	}
+	n, err := plexize.NewNamer(plexize.Templates(ts))
+	if err != nil {
+		t.Fatalf("Cannot create namer: %v\n", err)
+	}
+
+	np, err := convert("foo.s01e02.bar.abc", plexize.Media{}, options{dryRun: true, namer: n})
+	if want := filepath.Join("Foo", "Season 1", "Foo - S01E02.abc"); err != nil || np != want {
+		t.Errorf("convert: %s\ngot:  %s %v\nwant: %s", "foo.s01e02.bar.abc", np, err, want)
+	}
+
+	n, err = plexize.NewNamer(ts.over(plexize.Profiles["jellyfin"]))
+	if err != nil {
+		t.Fatalf("Cannot create namer: %v\n", err)
+	}
+	np, err = convert("foo.s00e02.bar.abc", plexize.Media{}, options{dryRun: true, namer: n})
+	if want := filepath.Join("Foo", "Season 0", "Foo - S00E02.abc"); err != nil || np != want {
+		t.Errorf("convert: %s\ngot:  %s %v\nwant: %s", "foo.s00e02.bar.abc", np, err, want)
+	}
+}
+
+func TestProcessPack(t *testing.T) {
+	const pn = "Breaking.Bad.S03.1080p.BluRay"
+
+	d, err := testDir()
+	if err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	defer os.RemoveAll(d)
+
+	ps := []string{"S03E01.mkv", "bb.s03e02.mkv", "03 - Sunset.mkv", "Breaking.Bad.S03E04.Green.Light.mkv", "notes.txt"}
+	if err := os.Mkdir(filepath.Join(d, pn), 0777); err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	for _, p := range ps {
+		if err := os.WriteFile(filepath.Join(d, pn, p), nil, 0666); err != nil {
+			t.Fatalf("Cannot create temp directory/file: %v\n", err)
+		}
+	}
+
+	processPack(filepath.Join(d, pn), options{outDir: filepath.Join(d, "target")})
+
+	for _, n := range []string{
+		filepath.Join(d, "target", "Breaking Bad", "Season 03", "Breaking Bad - s03e01.mkv"),
+		filepath.Join(d, "target", "Breaking Bad", "Season 03", "Breaking Bad - s03e02.mkv"),
+		filepath.Join(d, "target", "Breaking Bad", "Season 03", "Breaking Bad - s03e03 - Sunset.mkv"),
+		filepath.Join(d, "target", "Breaking Bad", "Season 03", "Breaking Bad - s03e04 - Green Light.mkv"),
+		filepath.Join(d, pn, "notes.txt"),
+	} {
+		if _, err := os.Stat(n); os.IsNotExist(err) {
+			t.Errorf("file does not exist:  %v\n", err)
+		}
+	}
+}
+
+func TestProcessSidecars(t *testing.T) {
+	ps := []string{
+		"Movie.2019.1080p.mkv",
+		"Movie.2019.1080p.en.srt",
+		"Movie.2019.1080p.English.forced.srt",
+		"Movie.2019.1080p.eng.sdh.srt",
+		"Movie.2019.1080p.fr.idx",
+		"Movie.2019.1080p.fr.sub",
+		"Movie.2019.1080p.ass",
+		"Movie.2019.1080p.nfo",
+	}
+
+	d, err := testDir(ps...)
+	if err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	defer os.RemoveAll(d)
+
+	process(filepath.Join(d, ps[0]), plexize.Media{}, options{separate: true})
+
+	for _, n := range []string{
+		filepath.Join(d, "Movie (2019)", "Movie (2019).mkv"),
+		filepath.Join(d, "Movie (2019)", "Movie (2019).en.srt"),
+		filepath.Join(d, "Movie (2019)", "Movie (2019).en.forced.srt"),
+		filepath.Join(d, "Movie (2019)", "Movie (2019).en.sdh.srt"),
+		filepath.Join(d, "Movie (2019)", "Movie (2019).fr.idx"),
+		filepath.Join(d, "Movie (2019)", "Movie (2019).fr.sub"),
+		filepath.Join(d, "Movie (2019)", "Movie (2019).ass"),
+		filepath.Join(d, "Movie.2019.1080p.nfo"),
+	} {
+		if _, err := os.Stat(n); os.IsNotExist(err) {
+			t.Errorf("file does not exist:  %v\n", err)
+		}
+	}
+}
+
+func TestProcessSidecarsLongestStem(t *testing.T) {
+	ps := []string{"Cars.2006.mkv", "Cars.2006.en.srt", "Cars.2006.2.mkv", "Cars.2006.2.en.srt"}
+
+	d, err := testDir(ps...)
+	if err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	defer os.RemoveAll(d)
+
+	process(filepath.Join(d, ps[0]), plexize.Media{}, options{separate: true})
+
+	for _, n := range []string{
+		filepath.Join(d, "Cars (2006)", "Cars (2006).en.srt"),
+		filepath.Join(d, "Cars.2006.2.en.srt"),
+	} {
+		if _, err := os.Stat(n); os.IsNotExist(err) {
+			t.Errorf("file does not exist:  %v\n", err)
+		}
+	}
+}
+
+func TestProcessExtras(t *testing.T) {
+	const pn = "Movie.2019.1080p.BluRay"
+
+	d, err := testDir()
+	if err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	defer os.RemoveAll(d)
+
+	ps := []string{
+		"Movie.2019.1080p.BluRay.mkv",
+		"Trailer.mkv",
+		"Featurette - Making of.mkv",
+		filepath.Join("Deleted Scenes", "Alternate.Ending.mkv"),
+	}
+	if err := os.MkdirAll(filepath.Join(d, pn, "Deleted Scenes"), 0777); err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	for _, p := range ps {
+		if err := os.WriteFile(filepath.Join(d, pn, p), nil, 0666); err != nil {
+			t.Fatalf("Cannot create temp directory/file: %v\n", err)
+		}
+	}
+
+	processPack(filepath.Join(d, pn), options{outDir: filepath.Join(d, "target")})
+
+	md := filepath.Join(d, "target", "Movie (2019)")
+	for _, n := range []string{
+		filepath.Join(md, "Movie (2019).mkv"),
+		filepath.Join(md, "Trailers", "Trailer.mkv"),
+		filepath.Join(md, "Featurettes", "Making Of.mkv"),
+		filepath.Join(md, "Deleted Scenes", "Alternate Ending.mkv"),
+	} {
+		if _, err := os.Stat(n); os.IsNotExist(err) {
+			t.Errorf("file does not exist:  %v\n", err)
+		}
+	}
+
+	d2, err := testDir("Movie.2019.1080p.mkv", "Movie.2019-trailer.mkv")
+	if err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	defer os.RemoveAll(d2)
+
+	md, ok := movieDir(d2, options{separate: true})
+	if !ok {
+		t.Fatalf("movie dir: %s\ngot:  %v\nwant: %v", d2, ok, true)
+	}
+	e, _ := plexize.ParseExtra("Movie.2019-trailer.mkv")
+	processExtra(filepath.Join(d2, "Movie.2019-trailer.mkv"), e, md, options{extrasSuffix: true})
+	if _, err := os.Stat(filepath.Join(d2, "Movie (2019)", "Trailer-trailer.mkv")); os.IsNotExist(err) {
+		t.Errorf("file does not exist:  %v\n", err)
+	}
+}
+
+func TestProcessFilesExtras(t *testing.T) {
+	ps := []string{"The.Big.Short.2015.mkv", "Other.Movie.2019.1080p.mkv", "Trailer.mkv"}
+
+	d, err := testDir(ps...)
+	if err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	defer os.RemoveAll(d)
+
+	if _, ok := movieDir(d, options{separate: true}); ok {
+		t.Errorf("movie dir: %s\ngot:  %v\nwant: %v", d, ok, false)
+	}
+
+	processFiles([]string{filepath.Join(d, ps[0]), filepath.Join(d, ps[2])}, false, options{separate: true})
+
+	for _, n := range []string{
+		filepath.Join(d, "The Big Short (2015)", "The Big Short (2015).mkv"),
+		filepath.Join(d, "The Big Short (2015)", "Trailers", "Trailer.mkv"),
+		filepath.Join(d, ps[1]),
+	} {
+		if _, err := os.Stat(n); os.IsNotExist(err) {
+			t.Errorf("file does not exist:  %v\n", err)
+		}
+	}
+}
+
+func TestProcessArtwork(t *testing.T) {
+	const pn = "Breaking.Bad.S03.1080p.BluRay"
+
+	d, err := testDir("Movie.2019.1080p.mkv", "cover.jpg", "backdrop.png")
+	if err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	defer os.RemoveAll(d)
+
+	ps := []string{"S03E01.mkv", "folder.jpg", "fanart.jpg", "season02-poster.jpg"}
+	if err := os.Mkdir(filepath.Join(d, pn), 0777); err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	for _, p := range ps {
+		if err := os.WriteFile(filepath.Join(d, pn, p), nil, 0666); err != nil {
+			t.Fatalf("Cannot create temp directory/file: %v\n", err)
+		}
+	}
+
+	o := options{separate: true, artwork: true, outDir: filepath.Join(d, "target")}
+	processPack(filepath.Join(d, pn), o)
+	np := process(filepath.Join(d, "Movie.2019.1080p.mkv"), plexize.Media{}, o)
+	processArtwork(filepath.Join(d, "Movie.2019.1080p.mkv"), np, plexize.Media{}, true, o)
+
+	for _, n := range []string{
+		filepath.Join(d, "target", "Breaking Bad", "Season 03", "Season03.jpg"),
+		filepath.Join(d, "target", "Breaking Bad", "Season 02", "Season02.jpg"),
+		filepath.Join(d, "target", "Breaking Bad", "fanart.jpg"),
+		filepath.Join(d, "target", "Movie (2019)", "poster.jpg"),
+		filepath.Join(d, "target", "Movie (2019)", "fanart.png"),
+	} {
+		if _, err := os.Stat(n); os.IsNotExist(err) {
+			t.Errorf("file does not exist:  %v\n", err)
+		}
+	}
+}
+
+func TestProcessArtworkFlat(t *testing.T) {
+	ps := []string{"A.2019.mkv", "B.2020.mkv", "A.2019-poster.jpg", "poster.jpg", "Some.Movie.jpg"}
+
+	d, err := testDir(ps...)
+	if err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	defer os.RemoveAll(d)
+
+	o := options{separate: true, artwork: true, outDir: filepath.Join(d, "target")}
+	processFiles([]string{filepath.Join(d, ps[0]), filepath.Join(d, ps[1])}, true, o)
+
+	for _, n := range []string{
+		filepath.Join(d, "target", "A (2019)", "poster.jpg"),
+		filepath.Join(d, "poster.jpg"),
+		filepath.Join(d, "Some.Movie.jpg"),
+	} {
+		if _, err := os.Stat(n); os.IsNotExist(err) {
+			t.Errorf("file does not exist:  %v\n", err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(d, "target", "B (2020)", "poster.jpg")); err == nil {
+		t.Errorf("file exists:  %s\n", filepath.Join(d, "target", "B (2020)", "poster.jpg"))
+	}
+}
+
+func BenchmarkConvert(b *testing.B) {
+	const n = "foo.s01e02.bar.abc"
+
+	d, err := testDir(n)
+	if err != nil {
+		b.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	defer os.RemoveAll(d)
+
+	for i := 0; i < b.N; i++ {
+		convert(n, plexize.Media{}, options{outDir: filepath.Join(d, "target")})
+	}
+}
+
+func testDir(paths ...string) (string, error) {
+	d, err := os.MkdirTemp("", "plexize")
+	if err != nil {
+		return "", err
+	}
+
+	for _, p := range paths {
+		if err := os.WriteFile(filepath.Join(d, p), nil, 0666); err != nil {
+			return "", err
+		}
+	}
+
+	return d, nil
+}
@@ -0,0 +1,17 @@
+//go:build !windows && !plan9
+
+package main
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// owner returns the owner of a file.
+func owner(fi fs.FileInfo) (uid, gid int, ok bool) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return int(st.Uid), int(st.Gid), true
+}
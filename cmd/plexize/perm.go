@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+)
+
+// parseMode parses an octal file mode, like 0664, 2775 or 0o2775, with setuid,
+// setgid and sticky bits.
+func parseMode(s string) (os.FileMode, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0o"), "0O")
+	m, err := strconv.ParseUint(strings.ReplaceAll(s, "_", ""), 8, 32)
+	if err != nil || m > 0o7777 {
+		return 0, fmt.Errorf("%s is not a file mode", s)
+	}
+
+	mode := os.FileMode(m) & os.ModePerm
+	for _, b := range []struct {
+		bit  uint64
+		mode os.FileMode
+	}{{0o4000, os.ModeSetuid}, {0o2000, os.ModeSetgid}, {0o1000, os.ModeSticky}} {
+		if m&b.bit != 0 {
+			mode |= b.mode
+		}
+	}
+	return mode, nil
+}
+
+// modeFlag is a flag of an octal file mode.
+type modeFlag os.FileMode
+
+func (m *modeFlag) String() string {
+	if *m == 0 {
+		return ""
+	}
+	return os.FileMode(*m).String()
+}
+
+func (m *modeFlag) Set(s string) error {
+	mode, err := parseMode(s)
+	if err != nil {
+		return err
+	}
+	*m = modeFlag(mode)
+	return nil
+}
+
+// lookupOwner returns uid and gid of a user and a group, by name or numeric ID.
+// The gid is the primary group of the user if the group is empty, or -1 if the
+// user has no primary group.
+func lookupOwner(name, group string) (uid, gid int, err error) {
+	gid = -1
+	var u *user.User
+	if id, err := strconv.Atoi(name); err == nil {
+		uid = id
+		u, _ = user.LookupId(name)
+	} else {
+		if u, err = user.Lookup(name); err != nil {
+			return -1, -1, err
+		}
+		if uid, err = strconv.Atoi(u.Uid); err != nil {
+			return -1, -1, fmt.Errorf("cannot parse the number: %v", err)
+		}
+	}
+	if u != nil && group == "" {
+		if gid, err = strconv.Atoi(u.Gid); err != nil {
+			return -1, -1, fmt.Errorf("cannot parse the number: %v", err)
+		}
+	}
+
+	if group == "" {
+		return uid, gid, nil
+	}
+	if id, err := strconv.Atoi(group); err == nil {
+		return uid, id, nil
+	}
+	g, err := user.LookupGroup(group)
+	if err != nil {
+		return -1, -1, err
+	}
+	if gid, err = strconv.Atoi(g.Gid); err != nil {
+		return -1, -1, fmt.Errorf("cannot parse the number: %v", err)
+	}
+	return uid, gid, nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestParseMode(t *testing.T) {
+	ts := []struct {
+		s   string
+		m   os.FileMode
+		err bool
+	}{
+		{"0660", 0660, false},
+		{"664", 0664, false},
+		{"0o770", 0770, false},
+		{"2775", os.ModeSetgid | 0775, false},
+		{"4755", os.ModeSetuid | 0755, false},
+		{"1777", os.ModeSticky | 0777, false},
+		{"0o1777777", 0, true},
+		{"rwxr-x---", 0, true},
+		{"0968", 0, true},
+	}
+
+	for _, tt := range ts {
+		m, err := parseMode(tt.s)
+		if (err != nil) != tt.err || m != tt.m {
+			t.Errorf("mode: %s\ngot:  %v %v\nwant: %v", tt.s, m, err, tt.m)
+		}
+	}
+}
+
+func TestLookupOwner(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skip("the OS does not support changing the file owner")
+	}
+
+	ts := []struct {
+		u, g     string
+		uid, gid int
+	}{
+		{"0", "0", 0, 0},
+		{"root", "", 0, 0},
+		{"root", "12345", 0, 12345},
+		{"12345", "", 12345, -1},
+		{"12345", "54321", 12345, 54321},
+	}
+
+	for _, tt := range ts {
+		uid, gid, err := lookupOwner(tt.u, tt.g)
+		if err != nil || uid != tt.uid || gid != tt.gid {
+			t.Errorf("owner: %s:%s\ngot:  %d:%d %v\nwant: %d:%d", tt.u, tt.g, uid, gid, err, tt.uid, tt.gid)
+		}
+	}
+
+	for _, o := range [][2]string{{"no-such-user-plexize", ""}, {"root", "no-such-group-plexize"}} {
+		if _, _, err := lookupOwner(o[0], o[1]); err == nil {
+			t.Errorf("owner: %s:%s\ngot:  nil\nwant: error", o[0], o[1])
+		}
+	}
+}
+
+func TestMakeDirMode(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skip("the OS does not support file modes")
+	}
+
+	d, err := testDir()
+	if err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	defer os.RemoveAll(d)
+
+	o := options{chmod: true, dirMode: os.ModeSetgid | 0750}
+	makeDir(o, "cannot make folder: %v\n", d, "Show", "Season 01")
+
+	for _, p := range []string{filepath.Join(d, "Show"), filepath.Join(d, "Show", "Season 01")} {
+		fi, err := os.Stat(p)
+		if err != nil {
+			t.Fatalf("folder does not exist:  %v\n", err)
+		}
+		if m := fi.Mode() & (os.ModePerm | os.ModeSetgid); m != o.dirMode {
+			t.Errorf("mode: %s\ngot:  %v\nwant: %v", p, m, o.dirMode)
+		}
+	}
+	if fi, err := os.Stat(d); err != nil || fi.Mode()&os.ModeSetgid != 0 {
+		t.Errorf("mode: %s\ngot:  %v %v\nwant: no setgid", d, fi.Mode(), err)
+	}
+}
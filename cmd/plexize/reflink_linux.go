@@ -0,0 +1,39 @@
+//go:build linux
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// ficlone is the FICLONE ioctl request of Linux.
+const ficlone = 0x40049409
+
+// reflink clones a file sharing its data blocks, on file systems which support
+// it, like Btrfs or XFS.
+func reflink(path, newPath string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	fi, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	dst, err := os.OpenFile(newPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, fi.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, dst.Fd(), ficlone, src.Fd())
+	if err := dst.Close(); errno == 0 && err != nil {
+		errno = syscall.EIO
+	}
+	if errno != 0 {
+		os.Remove(newPath)
+		return errno
+	}
+	return os.Chtimes(newPath, fi.ModTime(), fi.ModTime())
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package main
+
+import "errors"
+
+// reflink clones a file sharing its data blocks, which is not supported on the
+// OS.
+func reflink(path, newPath string) error {
+	return errors.New("reflinks are not supported on the OS")
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// Transfer modes, how to put files in their new paths. Except move, the
+// original files are left intact.
+const (
+	transferMove     = "move"
+	transferCopy     = "copy"
+	transferHardlink = "hardlink"
+	transferSymlink  = "symlink"
+	transferReflink  = "reflink"
+)
+
+// transferFlag is a flag of a transfer mode.
+type transferFlag string
+
+func (t *transferFlag) String() string {
+	return string(*t)
+}
+
+func (t *transferFlag) Set(s string) error {
+	switch s {
+	case transferMove, transferCopy, transferHardlink, transferSymlink, transferReflink:
+		*t = transferFlag(s)
+		return nil
+	}
+	return fmt.Errorf("unknown transfer mode %q (move, copy, hardlink, symlink or reflink)", s)
+}
+
+// transfer puts a file in its new path by the transfer mode, replacing an
+// existing file, and returns the used mode. Hardlinks to another device and
+// unsupported reflinks fall back to copies.
+func transfer(path, newPath string, mode transferFlag) (string, error) {
+	switch mode {
+	case transferCopy:
+		return transferCopy, copyFile(path, newPath)
+	case transferHardlink:
+		err := link(newPath, func(tmp string) error { return os.Link(path, tmp) })
+		if err == nil || !crossDevice(err) {
+			return transferHardlink, err
+		}
+		log.Printf("%s is on another device, copying %s\n", newPath, path)
+		return transferCopy, copyFile(path, newPath)
+	case transferSymlink:
+		p, err := filepath.Abs(path)
+		if err != nil {
+			return transferSymlink, err
+		}
+		return transferSymlink, link(newPath, func(tmp string) error { return os.Symlink(p, tmp) })
+	case transferReflink:
+		err := link(newPath, func(tmp string) error { return reflink(path, tmp) })
+		if err == nil {
+			return transferReflink, nil
+		}
+		log.Printf("cannot reflink %s (%v), copying it\n", path, err)
+		return transferCopy, copyFile(path, newPath)
+	}
+	return transferMove, rename(path, newPath)
+}
+
+// link makes a link (or a reflink) of a file in a temporary file next to the
+// new path by mk, and renames it to the new path, like copyFile does.
+func link(newPath string, mk func(tmp string) error) error {
+	tmp := partPath(newPath)
+	if err := mk(tmp); err != nil {
+		return err
+	}
+	err := os.Rename(tmp, newPath)
+	// Renaming a hardlink to another link of the same file does nothing.
+	os.Remove(tmp)
+	return err
+}
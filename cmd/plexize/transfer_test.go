@@ -0,0 +1,126 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/m4ns0ur/plexize"
+)
+
+func TestTransfer(t *testing.T) {
+	d, err := testDir()
+	if err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	defer os.RemoveAll(d)
+
+	p := filepath.Join(d, "Movie.2019.mkv")
+	if err := os.WriteFile(p, []byte("movie"), 0666); err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+
+	ts := []struct {
+		t    transferFlag
+		m    []string
+		same bool
+	}{
+		{transferCopy, []string{transferCopy}, false},
+		{transferHardlink, []string{transferHardlink}, true},
+		{transferSymlink, []string{transferSymlink}, true},
+		{transferReflink, []string{transferReflink, transferCopy}, false},
+	}
+
+	for _, tt := range ts {
+		np := filepath.Join(d, string(tt.t)+".mkv")
+		m, err := transfer(p, np, tt.t)
+		if err != nil || m != tt.m[0] && (len(tt.m) == 1 || m != tt.m[1]) {
+			t.Errorf("transfer: %s\ngot:  %s %v\nwant: %v nil", tt.t, m, err, tt.m)
+		}
+		if b, err := os.ReadFile(np); err != nil || string(b) != "movie" {
+			t.Errorf("content: %s\ngot:  %q %v\nwant: %q", np, b, err, "movie")
+		}
+		fi, _ := os.Stat(p)
+		nfi, _ := os.Stat(np)
+		if same := os.SameFile(fi, nfi); same != tt.same {
+			t.Errorf("same file: %s\ngot:  %v\nwant: %v", tt.t, same, tt.same)
+		}
+	}
+	if l, err := os.Readlink(filepath.Join(d, "symlink.mkv")); err != nil || l != p {
+		t.Errorf("symlink: %s\ngot:  %s %v\nwant: %s", p, l, err, p)
+	}
+}
+
+func TestProcessTransfer(t *testing.T) {
+	ps := []string{"Movie.2019.1080p.mkv", "Movie.2019.1080p.en.srt"}
+
+	d, err := testDir(ps...)
+	if err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	defer os.RemoveAll(d)
+	if err := os.Chmod(filepath.Join(d, ps[0]), 0644); err != nil {
+		t.Fatalf("Cannot change mode of temp file: %v\n", err)
+	}
+
+	jd := filepath.Join(d, "journal")
+	o := options{separate: true, chmod: true, transfer: transferHardlink, outDir: filepath.Join(d, "target"), journal: newJournal(jd)}
+	process(filepath.Join(d, ps[0]), plexize.Media{}, o)
+	o.journal.close()
+
+	for _, n := range []string{
+		filepath.Join(d, ps[0]),
+		filepath.Join(d, ps[1]),
+		filepath.Join(d, "target", "Movie (2019)", "Movie (2019).mkv"),
+		filepath.Join(d, "target", "Movie (2019)", "Movie (2019).en.srt"),
+	} {
+		if _, err := os.Stat(n); os.IsNotExist(err) {
+			t.Errorf("file does not exist:  %v\n", err)
+		}
+	}
+	// The mode of a hardlink is the mode of the original file.
+	if fi, err := os.Stat(filepath.Join(d, ps[0])); err == nil && fi.Mode().Perm() != 0644 {
+		t.Errorf("mode: %s\ngot:  %v\nwant: %v", ps[0], fi.Mode().Perm(), os.FileMode(0644))
+	}
+
+	if err := undo(jd, o.journal.id, options{}); err != nil {
+		t.Fatalf("undo: %s\ngot:  %v\nwant: nil", o.journal.id, err)
+	}
+	if _, err := os.Stat(filepath.Join(d, "target")); !os.IsNotExist(err) {
+		t.Errorf("dir exists:  %s\n", filepath.Join(d, "target"))
+	}
+	for _, p := range ps {
+		if _, err := os.Stat(filepath.Join(d, p)); os.IsNotExist(err) {
+			t.Errorf("file does not exist:  %v\n", err)
+		}
+	}
+}
+
+func TestTransferReplace(t *testing.T) {
+	d, err := testDir()
+	if err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	defer os.RemoveAll(d)
+
+	p := filepath.Join(d, "Movie.2019.mkv")
+	if err := os.WriteFile(p, []byte("movie"), 0666); err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+
+	for _, tt := range []transferFlag{transferCopy, transferHardlink, transferSymlink, transferReflink} {
+		np := filepath.Join(d, string(tt)+".mkv")
+		if err := os.WriteFile(np, []byte("old"), 0666); err != nil {
+			t.Fatalf("Cannot create temp directory/file: %v\n", err)
+		}
+		if m, err := transfer(p, np, tt); err != nil {
+			t.Errorf("transfer: %s\ngot:  %s %v\nwant: nil", tt, m, err)
+		}
+		if b, err := os.ReadFile(np); err != nil || string(b) != "movie" {
+			t.Errorf("content: %s\ngot:  %q %v\nwant: %q", np, b, err, "movie")
+		}
+		if _, err := os.Lstat(partPath(np)); !os.IsNotExist(err) {
+			t.Errorf("file exists:  %s\n", partPath(np))
+		}
+	}
+}
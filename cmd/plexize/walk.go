@@ -0,0 +1,87 @@
+package main
+
+import (
+	"io/fs"
+	"log"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// globs is a repeatable flag of glob patterns.
+type globs []string
+
+func (g *globs) String() string {
+	return strings.Join(*g, ",")
+}
+
+func (g *globs) Set(s string) error {
+	if _, err := filepath.Match(s, ""); err != nil {
+		return err
+	}
+	*g = append(*g, s)
+	return nil
+}
+
+// match reports whether a file base name or its path relative to the walked
+// directory matches any of the patterns.
+func (g globs) match(rel string) bool {
+	for _, p := range g {
+		if ok, _ := filepath.Match(p, filepath.Base(rel)); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(p, rel); ok {
+			return true
+		}
+	}
+	return false
+}
+
+var junkDirs = map[string]bool{
+	"sample": true, "samples": true, "proof": true, "screens": true, "screenshots": true,
+	"$recycle.bin": true, "lost+found": true,
+}
+
+var sampleRe = regexp.MustCompile(`(?i)(?:^|[ ._\-])sample(?:[ ._\-]|$)`)
+
+// walk returns media files of a directory tree, skipping samples, hidden and
+// junk folders, and files not selected by the include/exclude patterns.
+func walk(root string, o options) []string {
+	var ps []string
+	filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			log.Printf("cannot walk %s: %v\n", p, err)
+			return nil
+		}
+		if d.IsDir() {
+			if p != root && junkDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if media(root, p, o) {
+			ps = append(ps, p)
+		}
+		return nil
+	})
+	return ps
+}
+
+// junkDir reports whether a folder is hidden or junk, like Sample or @eaDir.
+func junkDir(name string) bool {
+	return strings.HasPrefix(name, ".") || strings.HasPrefix(name, "@") || junkDirs[strings.ToLower(name)]
+}
+
+// media reports whether a file in a walked directory is a video file, not a
+// sample, selected by the include/exclude patterns.
+func media(root, path string, o options) bool {
+	n := filepath.Base(path)
+	if !videoExts[strings.ToLower(filepath.Ext(n))] || sampleRe.MatchString(strings.TrimSuffix(n, filepath.Ext(n))) {
+		return false
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		rel = n
+	}
+	return !(len(o.include) > 0 && !o.include.match(rel) || o.exclude.match(rel))
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWalk(t *testing.T) {
+	d, err := testDir()
+	if err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	defer os.RemoveAll(d)
+
+	ps := []string{
+		filepath.Join("Movie.2019.1080p", "Movie.2019.1080p.mkv"),
+		filepath.Join("Movie.2019.1080p", "movie.2019.1080p-sample.mkv"),
+		filepath.Join("Movie.2019.1080p", "Sample", "movie.mkv"),
+		filepath.Join("Movie.2019.1080p", "Movie.2019.1080p.nfo"),
+		filepath.Join("Show.S01", "Show.S01E01.avi"),
+		filepath.Join("Show.S01", "Show.S01E02.mp4"),
+		filepath.Join(".hidden", "Other.2020.mkv"),
+		"Sampler.2021.mkv",
+	}
+	for _, p := range ps {
+		if err := os.MkdirAll(filepath.Join(d, filepath.Dir(p)), 0777); err != nil {
+			t.Fatalf("Cannot create temp directory/file: %v\n", err)
+		}
+		if err := os.WriteFile(filepath.Join(d, p), nil, 0666); err != nil {
+			t.Fatalf("Cannot create temp directory/file: %v\n", err)
+		}
+	}
+
+	ts := []struct {
+		i, e globs
+		ps   []string
+	}{
+		{
+			nil, nil,
+			[]string{ps[0], ps[7], ps[4], ps[5]},
+		},
+		{
+			nil, globs{"*.avi", "Movie*/*"},
+			[]string{ps[7], ps[5]},
+		},
+		{
+			globs{"Show.S01/*"}, globs{"*.avi"},
+			[]string{ps[5]},
+		},
+	}
+
+	for _, tt := range ts {
+		var want []string
+		for _, p := range tt.ps {
+			want = append(want, filepath.Join(d, p))
+		}
+		if got := walk(d, options{include: tt.i, exclude: tt.e}); !reflect.DeepEqual(got, want) {
+			t.Errorf("walk: %v %v\ngot:  %v\nwant: %v", tt.i, tt.e, got, want)
+		}
+	}
+}
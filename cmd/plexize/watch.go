@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// watcher reports created, written or moved files and folders of watched
+// directory trees.
+type watcher interface {
+	Events() <-chan event
+	Close() error
+}
+
+// event is a file or folder change. Closed is set if the writer of the file has
+// closed it, or the file is moved in.
+type event struct {
+	path   string
+	closed bool
+}
+
+var (
+	pollInterval = 2 * time.Second
+	settleTime   = 10 * time.Second
+)
+
+type pending struct {
+	size    int64
+	modTime time.Time
+	since   time.Time
+	closed  bool
+}
+
+// watch watches directory trees, and processes video files when they are
+// stable, until the context is canceled. A file is stable if it is unchanged
+// for a poll interval after its writer has closed it, or for the settle time.
+func watch(ctx context.Context, dirs []string, o options) error {
+	w, err := newWatcher(dirs)
+	if err != nil {
+		log.Printf("cannot watch with OS notifications, polling instead: %v\n", err)
+		w = newPoller(dirs)
+	}
+	defer w.Close()
+
+	ps := make(map[string]*pending)
+	// New paths of processed files, not to process them again if they are in
+	// a watched folder.
+	done := make(map[string]bool)
+	add := func(p string, closed bool) {
+		if done[p] || !media(rootOf(dirs, p), p, o) {
+			return
+		}
+		fi, err := os.Stat(p)
+		if err != nil {
+			return
+		}
+		if st, ok := ps[p]; ok {
+			st.closed = st.closed || closed
+			return
+		}
+		log.Printf("new file %s\n", p)
+		ps[p] = &pending{size: fi.Size(), modTime: fi.ModTime(), since: time.Now(), closed: closed}
+	}
+
+	for _, d := range dirs {
+		log.Printf("watching %s\n", d)
+		for _, p := range walk(d, o) {
+			add(p, false)
+		}
+	}
+
+	t := time.NewTicker(pollInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("stopping the watch")
+			return nil
+		case e, ok := <-w.Events():
+			if !ok {
+				return errors.New("the watcher is closed")
+			}
+			if fi, err := os.Stat(e.path); err == nil && fi.IsDir() {
+				if !junkDir(fi.Name()) {
+					for _, p := range walk(e.path, o) {
+						add(p, false)
+					}
+				}
+				continue
+			}
+			add(e.path, e.closed)
+		case now := <-t.C:
+			var stable []string
+			for p, st := range ps {
+				fi, err := os.Stat(p)
+				if err != nil {
+					delete(ps, p)
+					continue
+				}
+				if fi.Size() != st.size || !fi.ModTime().Equal(st.modTime) {
+					st.size, st.modTime, st.since = fi.Size(), fi.ModTime(), now
+					continue
+				}
+				if st.closed && now.Sub(st.since) >= pollInterval || now.Sub(st.since) >= settleTime {
+					stable = append(stable, p)
+					delete(ps, p)
+				}
+			}
+			if len(stable) == 0 {
+				continue
+			}
+			sort.Strings(stable)
+			log.Printf("processing %d stable file(s)\n", len(stable))
+			for _, np := range processFiles(stable, true, o) {
+				done[np] = true
+			}
+		}
+	}
+}
+
+// rootOf returns the watched directory of a path.
+func rootOf(dirs []string, path string) string {
+	for _, d := range dirs {
+		if rel, err := filepath.Rel(d, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return d
+		}
+	}
+	return filepath.Dir(path)
+}
+
+// poller is a watcher which walks the directory trees in every poll interval,
+// reporting new or changed files.
+type poller struct {
+	events chan event
+	done   chan struct{}
+}
+
+func newPoller(dirs []string) *poller {
+	w := &poller{events: make(chan event), done: make(chan struct{})}
+	go w.poll(dirs)
+	return w
+}
+
+func (w *poller) Events() <-chan event {
+	return w.events
+}
+
+func (w *poller) Close() error {
+	close(w.done)
+	return nil
+}
+
+func (w *poller) poll(dirs []string) {
+	type stat struct {
+		size    int64
+		modTime time.Time
+	}
+	seen := make(map[string]stat)
+
+	t := time.NewTicker(pollInterval)
+	defer t.Stop()
+	for {
+		for _, d := range dirs {
+			filepath.WalkDir(d, func(p string, e fs.DirEntry, err error) error {
+				if err != nil {
+					return nil
+				}
+				if e.IsDir() {
+					if p != d && junkDir(e.Name()) {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+				fi, err := e.Info()
+				if err != nil {
+					return nil
+				}
+				s := stat{fi.Size(), fi.ModTime()}
+				if seen[p] == s {
+					return nil
+				}
+				seen[p] = s
+				select {
+				case w.events <- event{path: p}:
+					return nil
+				case <-w.done:
+					return filepath.SkipAll
+				}
+			})
+		}
+
+		select {
+		case <-t.C:
+		case <-w.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,124 @@
+//go:build linux
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+const inotifyMask = syscall.IN_CREATE | syscall.IN_CLOSE_WRITE | syscall.IN_MOVED_TO
+
+// inotify is a watcher using Linux inotify, watching every folder of the
+// directory trees.
+type inotify struct {
+	fd     int
+	f      *os.File
+	dirs   []string
+	wds    map[int32]string
+	events chan event
+	done   chan struct{}
+}
+
+func newWatcher(dirs []string) (watcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	w := &inotify{
+		fd:     fd,
+		f:      os.NewFile(uintptr(fd), "inotify"),
+		dirs:   dirs,
+		wds:    make(map[int32]string),
+		events: make(chan event),
+		done:   make(chan struct{}),
+	}
+	for _, d := range dirs {
+		if err := w.addTree(d); err != nil {
+			w.f.Close()
+			return nil, err
+		}
+	}
+	go w.read()
+	return w, nil
+}
+
+func (w *inotify) Events() <-chan event {
+	return w.events
+}
+
+func (w *inotify) Close() error {
+	close(w.done)
+	return w.f.Close()
+}
+
+func (w *inotify) addTree(root string) error {
+	return filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			if p == root {
+				return err
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if p != root && junkDir(d.Name()) {
+			return filepath.SkipDir
+		}
+		wd, err := syscall.InotifyAddWatch(w.fd, p, inotifyMask)
+		if err != nil {
+			return err
+		}
+		w.wds[int32(wd)] = p
+		return nil
+	})
+}
+
+func (w *inotify) read() {
+	defer close(w.events)
+
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+	for {
+		n, err := w.f.Read(buf)
+		if err != nil {
+			return
+		}
+		for i := 0; i+syscall.SizeofInotifyEvent <= n; {
+			e := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[i]))
+			name := strings.TrimRight(string(buf[i+syscall.SizeofInotifyEvent:i+syscall.SizeofInotifyEvent+int(e.Len)]), "\x00")
+			i += syscall.SizeofInotifyEvent + int(e.Len)
+
+			var es []event
+			switch {
+			case e.Mask&syscall.IN_Q_OVERFLOW != 0:
+				// Some events are lost, rescan the directory trees.
+				for _, d := range w.dirs {
+					es = append(es, event{path: d})
+				}
+			case e.Mask&syscall.IN_IGNORED != 0:
+				delete(w.wds, e.Wd)
+			case w.wds[e.Wd] == "":
+			case e.Mask&syscall.IN_ISDIR != 0:
+				p := filepath.Join(w.wds[e.Wd], name)
+				if !junkDir(name) {
+					w.addTree(p)
+				}
+				es = append(es, event{path: p})
+			default:
+				p := filepath.Join(w.wds[e.Wd], name)
+				es = append(es, event{path: p, closed: e.Mask&(syscall.IN_CLOSE_WRITE|syscall.IN_MOVED_TO) != 0})
+			}
+			for _, e := range es {
+				select {
+				case w.events <- e:
+				case <-w.done:
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !linux
+
+package main
+
+func newWatcher(dirs []string) (watcher, error) {
+	return newPoller(dirs), nil
+}
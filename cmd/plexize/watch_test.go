@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	defer func(p, s time.Duration) { pollInterval, settleTime = p, s }(pollInterval, settleTime)
+	pollInterval, settleTime = 20*time.Millisecond, 100*time.Millisecond
+
+	d, err := testDir("Old.Movie.2018.mkv")
+	if err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	defer os.RemoveAll(d)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error)
+	go func() {
+		errc <- watch(ctx, []string{d}, options{separate: true})
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	if err := os.MkdirAll(filepath.Join(d, "Movie.2019.1080p"), 0777); err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	if err := os.WriteFile(filepath.Join(d, "Movie.2019.1080p", "Movie.2019.1080p.mkv"), []byte("movie"), 0666); err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+
+	ns := []string{
+		filepath.Join(d, "Old Movie (2018)", "Old Movie (2018).mkv"),
+		filepath.Join(d, "Movie.2019.1080p", "Movie (2019)", "Movie (2019).mkv"),
+	}
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		_, err0 := os.Stat(ns[0])
+		_, err1 := os.Stat(ns[1])
+		if err0 == nil && err1 == nil {
+			break
+		}
+	}
+	cancel()
+	if err := <-errc; err != nil {
+		t.Errorf("watch: %v\n", err)
+	}
+
+	for _, n := range ns {
+		if _, err := os.Stat(n); os.IsNotExist(err) {
+			t.Errorf("file does not exist:  %v\n", err)
+		}
+	}
+}
+
+func TestPoller(t *testing.T) {
+	defer func(p time.Duration) { pollInterval = p }(pollInterval)
+	pollInterval = 20 * time.Millisecond
+
+	d, err := testDir("a.mkv")
+	if err != nil {
+		t.Fatalf("Cannot create temp directory/file: %v\n", err)
+	}
+	defer os.RemoveAll(d)
+
+	w := newPoller([]string{d})
+	defer w.Close()
+
+	for _, n := range []string{"a.mkv", "b.mkv"} {
+		if n == "b.mkv" {
+			if err := os.WriteFile(filepath.Join(d, n), nil, 0666); err != nil {
+				t.Fatalf("Cannot create temp directory/file: %v\n", err)
+			}
+		}
+		select {
+		case e := <-w.Events():
+			if e.path != filepath.Join(d, n) {
+				t.Errorf("event: %s\ngot:  %s\nwant: %s", n, e.path, filepath.Join(d, n))
+			}
+		case <-time.After(5 * time.Second):
+			t.Errorf("event: %s\ngot:  timeout\nwant: %s", n, filepath.Join(d, n))
+		}
+	}
+}
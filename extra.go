@@ -0,0 +1,154 @@
+package plexize
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Extra is a movie extra, like a trailer or a featurette.
+type Extra struct {
+	Type  string // Plex extras folder, like Trailers or Deleted Scenes.
+	Title string // Like "Making Of", empty if the extra has no name.
+	Ext   string
+}
+
+type extraType struct {
+	re                   *regexp.Regexp
+	folder, name, suffix string
+}
+
+var extraTypes = [...]extraType{
+	{regexp.MustCompile(`(?i)^behind[ ._\-]?the[ ._\-]?scenes$|^bts$`), "Behind The Scenes", "Behind The Scenes", "behindthescenes"},
+	{regexp.MustCompile(`(?i)^deleted(?:[ ._\-]?scenes?)?$`), "Deleted Scenes", "Deleted Scene", "deleted"},
+	{regexp.MustCompile(`(?i)^featurettes?$|^making[ ._\-]?of$`), "Featurettes", "Featurette", "featurette"},
+	{regexp.MustCompile(`(?i)^interviews?$`), "Interviews", "Interview", "interview"},
+	{regexp.MustCompile(`(?i)^shorts?$`), "Shorts", "Short", "short"},
+	{regexp.MustCompile(`(?i)^trailers?$|^teasers?$`), "Trailers", "Trailer", "trailer"},
+}
+
+var (
+	extraPrefixRe = regexp.MustCompile(`(?i)^(behind the scenes|deleted scenes?|featurettes?|making of|interviews?|trailers?|teasers?)(?: +- +(.+)|[ ._]?(\d{1,2}))?$`)
+	extraSuffixRe = regexp.MustCompile(`(?i)^(.+)-(behindthescenes|deleted|featurette|interview|short|trailer)$`)
+)
+
+// ParseExtra parses a movie extra file name, and reports whether it is an
+// extra. Extras are recognized by their folder, like "Deleted Scenes/Cut.mkv",
+// by their type alone or as a prefix, like "Trailer 2.mkv" or "Featurette -
+// Making of.mkv", or by a Plex suffix, like "Cut-deleted.mkv". Names which only
+// contain an extra word, like "Interview.With.The.Vampire.mkv" or
+// "The.Big.Short.mkv", and episodes are not extras.
+func ParseExtra(filename string) (Extra, bool) {
+	return (*Parser)(nil).ParseExtra(filename)
+}
+
+// ParseExtra is like the package ParseExtra, removing the junk words of the
+// parser.
+func (p *Parser) ParseExtra(filename string) (Extra, bool) {
+	var e Extra
+
+	dir, file := filepath.Split(filename)
+	e.Ext = strings.ToLower(filepath.Ext(file))
+	stem := strings.TrimSuffix(file, filepath.Ext(file))
+	if p != nil {
+		for _, j := range p.junk {
+			stem = strings.ReplaceAll(stem, j, "")
+		}
+	}
+
+	if t, ok := extraTypeOf(filepath.Base(dir)); ok {
+		e.Type = t.folder
+		e.Title = extraTitle(stem, t)
+		return e, true
+	}
+
+	if x := extraPrefixRe.FindStringSubmatch(stem); x != nil {
+		m := p.parse(x[2])
+		if m.Year != "" || m.IsEpisode() {
+			return e, false
+		}
+		t, _ := extraTypeOf(x[1])
+		e.Type = t.folder
+		e.Title = extraTitle(x[2]+x[3], t)
+		return e, true
+	}
+
+	if s := extraSuffixRe.FindStringSubmatch(stem); s != nil {
+		m := p.parse(s[1])
+		if m.IsEpisode() {
+			return e, false
+		}
+		t, _ := extraTypeOf(s[2])
+		e.Type = t.folder
+		// The rest of a name like Movie.2019-trailer is the movie itself.
+		if m.Year == "" {
+			e.Title = extraTitle(s[1], t)
+		}
+		return e, true
+	}
+
+	return e, false
+}
+
+// IsExtraDir reports whether a folder name is a Plex extras folder, like
+// "Deleted Scenes" or "featurettes".
+func IsExtraDir(dirname string) bool {
+	_, ok := extraTypeOf(filepath.Base(filepath.Clean(dirname)))
+	return ok
+}
+
+// Name returns the file name of the extra in the Plex extras folder, like
+// "Making Of.mkv".
+func (e Extra) Name() string {
+	return e.title() + e.Ext
+}
+
+// SuffixName returns the file name of the extra next to the movie, with the
+// Plex extra suffix, like "Making Of-featurette.mkv".
+func (e Extra) SuffixName() string {
+	for _, t := range extraTypes {
+		if t.folder == e.Type {
+			return e.title() + "-" + t.suffix + e.Ext
+		}
+	}
+	return e.Name()
+}
+
+func (e Extra) title() string {
+	if e.Title != "" {
+		return e.Title
+	}
+	for _, t := range extraTypes {
+		if t.folder == e.Type {
+			return t.name
+		}
+	}
+	return e.Type
+}
+
+func extraTypeOf(s string) (extraType, bool) {
+	s = strings.ToLower(s)
+	if s == "behindthescenes" {
+		s = "bts"
+	}
+	for _, t := range extraTypes {
+		if t.re.MatchString(s) {
+			return t, true
+		}
+	}
+	return extraType{}, false
+}
+
+// extraTitle returns a clean title of an extra, like "Trailer 2" for "2".
+func extraTitle(s string, t extraType) string {
+	s = strings.Join(strings.FieldsFunc(s, func(r rune) bool { return r == '.' || r == '_' || r == ' ' }), " ")
+	s = strings.Trim(s, " -")
+	if s == "" {
+		return ""
+	}
+	if strings.IndexFunc(s, unicode.IsLetter) == -1 {
+		return t.name + " " + s
+	}
+	return strings.Title(s)
+}
@@ -0,0 +1,148 @@
+package plexize
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestParseExtra(t *testing.T) {
+	ts := []struct {
+		f     string
+		ok    bool
+		e     Extra
+		n, sn string
+	}{
+		{
+			"Trailer.mkv", true,
+			Extra{Type: "Trailers", Ext: ".mkv"},
+			"Trailer.mkv", "Trailer-trailer.mkv",
+		},
+		{
+			"Trailer 2.mkv", true,
+			Extra{Type: "Trailers", Title: "Trailer 2", Ext: ".mkv"},
+			"Trailer 2.mkv", "Trailer 2-trailer.mkv",
+		},
+		{
+			"Featurette - Making of.mkv", true,
+			Extra{Type: "Featurettes", Title: "Making Of", Ext: ".mkv"},
+			"Making Of.mkv", "Making Of-featurette.mkv",
+		},
+		{
+			filepath.Join("Deleted Scenes", "Alternate.Ending.mkv"), true,
+			Extra{Type: "Deleted Scenes", Title: "Alternate Ending", Ext: ".mkv"},
+			"Alternate Ending.mkv", "Alternate Ending-deleted.mkv",
+		},
+		{
+			filepath.Join("Behind The Scenes", "VFX.mkv"), true,
+			Extra{Type: "Behind The Scenes", Title: "VFX", Ext: ".mkv"},
+			"VFX.mkv", "VFX-behindthescenes.mkv",
+		},
+		{
+			"Movie.2019-trailer.mkv", true,
+			Extra{Type: "Trailers", Ext: ".mkv"},
+			"Trailer.mkv", "Trailer-trailer.mkv",
+		},
+		{
+			"Director interview-interview.mp4", true,
+			Extra{Type: "Interviews", Title: "Director Interview", Ext: ".mp4"},
+			"Director Interview.mp4", "Director Interview-interview.mp4",
+		},
+		{
+			"Interview.With.The.Vampire.1994.720p.mkv", false,
+			Extra{}, "", "",
+		},
+		{
+			"Short.Circuit.1986.mkv", false,
+			Extra{}, "", "",
+		},
+		{
+			"Trailer.Park.Boys.S01E01.mkv", false,
+			Extra{}, "", "",
+		},
+		{
+			"The.Big.Short.mkv", false,
+			Extra{}, "", "",
+		},
+		{
+			"The.Interview.mkv", false,
+			Extra{}, "", "",
+		},
+		{
+			"Interview.With.The.Vampire.mkv", false,
+			Extra{}, "", "",
+		},
+		{
+			"Movie.2019.Trailer.mkv", false,
+			Extra{}, "", "",
+		},
+		{
+			"Show.S01E01.Trailer.mkv", false,
+			Extra{}, "", "",
+		},
+		{
+			"Show.S01E01-trailer.mkv", false,
+			Extra{}, "", "",
+		},
+	}
+
+	for _, tt := range ts {
+		e, ok := ParseExtra(tt.f)
+		if ok != tt.ok {
+			t.Errorf("extra: %s\ngot:  %v\nwant: %v", tt.f, ok, tt.ok)
+		}
+		if !ok {
+			continue
+		}
+		if e != tt.e {
+			t.Errorf("extra: %s\ngot:  %+v\nwant: %+v", tt.f, e, tt.e)
+		}
+		if n := e.Name(); n != tt.n {
+			t.Errorf("name: %s\ngot:  %s\nwant: %s", tt.f, n, tt.n)
+		}
+		if sn := e.SuffixName(); sn != tt.sn {
+			t.Errorf("suffix name: %s\ngot:  %s\nwant: %s", tt.f, sn, tt.sn)
+		}
+	}
+}
+
+func TestParserExtra(t *testing.T) {
+	p := NewParser([]string{"www.Site2021.com"})
+
+	ts := []struct {
+		f  string
+		ok bool
+		e  Extra
+	}{
+		{"Trailer - Cut www.Site2021.com.mkv", true, Extra{"Trailers", "Cut", ".mkv"}},
+		{"Deleted Scenes/Cut www.Site2021.com.mkv", true, Extra{"Deleted Scenes", "Cut", ".mkv"}},
+		{"Trailer - Other Movie 2019.mkv", false, Extra{}},
+	}
+
+	for _, tt := range ts {
+		e, ok := p.ParseExtra(tt.f)
+		if ok != tt.ok || e != tt.e && ok {
+			t.Errorf("extra: %s\ngot:  %+v %v\nwant: %+v %v", tt.f, e, ok, tt.e, tt.ok)
+		}
+	}
+	if _, ok := ParseExtra("Trailer - Cut www.Site2021.com.mkv"); ok {
+		t.Errorf("default extra: %s\ngot:  %v\nwant: %v", "Trailer - Cut www.Site2021.com.mkv", ok, false)
+	}
+}
+
+func TestIsExtraDir(t *testing.T) {
+	ts := []struct {
+		d  string
+		ok bool
+	}{
+		{"Deleted Scenes", true},
+		{filepath.Join("Movie (2019)", "featurettes") + string(filepath.Separator), true},
+		{"Behind.The.Scenes", true},
+		{"Movie (2019)", false},
+	}
+
+	for _, tt := range ts {
+		if ok := IsExtraDir(tt.d); ok != tt.ok {
+			t.Errorf("extra dir: %s\ngot:  %v\nwant: %v", tt.d, ok, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package plexize
+
+import "strings"
+
+// Languages are audio and hard-coded subtitle languages of a release, as ISO
+// 639-1 codes, like en or fr. Multi or dual audio releases have mul, and hard
+// subs in unknown language have und as ISO 639-2 codes.
+type Languages struct {
+	Audio     []string
+	Subtitles []string
+}
+
+// Tag returns languages in file name tag syntax, like [fr+en][sub-ko].
+func (l Languages) Tag() string {
+	var t string
+	if len(l.Audio) != 0 {
+		t = "[" + strings.Join(l.Audio, "+") + "]"
+	}
+	if len(l.Subtitles) != 0 {
+		t += "[sub-" + strings.Join(l.Subtitles, "+") + "]"
+	}
+	return t
+}
+
+// languageNames maps lower case language names and ISO 639-2 codes to ISO 639-1
+// codes.
+var languageNames = map[string]string{
+	"english": "en", "eng": "en",
+	"french": "fr", "fre": "fr", "fra": "fr", "truefrench": "fr", "vff": "fr", "vfq": "fr", "vf": "fr", "vf2": "fr",
+	"german": "de", "ger": "de", "deu": "de",
+	"spanish": "es", "spa": "es", "esp": "es", "castellano": "es", "latino": "es",
+	"italian": "it", "ita": "it",
+	"russian": "ru", "rus": "ru",
+	"hindi": "hi", "hin": "hi",
+	"tamil": "ta", "tam": "ta",
+	"telugu": "te", "tel": "te",
+	"japanese": "ja", "jpn": "ja", "jap": "ja",
+	"korean": "ko", "kor": "ko",
+	"chinese": "zh", "chi": "zh", "zho": "zh", "chs": "zh", "cht": "zh", "mandarin": "zh", "cantonese": "zh",
+	"portuguese": "pt", "por": "pt",
+	"dutch": "nl", "dut": "nl", "nld": "nl", "flemish": "nl",
+	"swedish": "sv", "swe": "sv",
+	"norwegian": "no", "nor": "no",
+	"danish": "da", "dan": "da",
+	"finnish": "fi", "fin": "fi",
+	"polish": "pl", "pol": "pl",
+	"turkish": "tr", "tur": "tr",
+	"arabic": "ar", "ara": "ar",
+	"persian": "fa", "farsi": "fa", "per": "fa", "fas": "fa",
+	"hebrew": "he", "heb": "he",
+	"greek": "el", "gre": "el", "ell": "el",
+	"czech": "cs", "cze": "cs", "ces": "cs",
+	"hungarian": "hu", "hun": "hu",
+	"ukrainian": "uk", "ukr": "uk",
+	"thai": "th", "tha": "th",
+	"vietnamese": "vi", "vie": "vi",
+	"multi": "mul",
+}
+
+// languageCodes are ISO 639-1 codes which are unlikely to be words, matched in
+// upper case only, like FR.
+var languageCodes = map[string]bool{
+	"EN": true, "FR": true, "DE": true, "ES": true, "RU": true, "JA": true, "KO": true, "ZH": true,
+	"PT": true, "NL": true, "SV": true, "DA": true, "FI": true, "PL": true, "TR": true, "AR": true,
+}
+
+// languageOf returns the language at token i, whether it is a subtitle
+// language, and the number of tokens used. Languages in any case are only
+// recognized right after the end of the title, like a year, tech or release
+// junk, or another language. Elsewhere, only upper case languages in a mixed
+// case name are recognized, so titles like "The English Patient", "THE ENGLISH
+// PATIENT" or episode titles like "The Chinese Restaurant" are kept.
+func languageOf(ts []string, i int, done bool) (string, bool, int) {
+	t := strings.Trim(ts[i], " -[]()")
+	if !(done && afterJunk(ts, i)) && (t != strings.ToUpper(t) || !mixedCase(ts[:i])) {
+		return "", false, 0
+	}
+
+	if languageCodes[t] {
+		return strings.ToLower(t), false, 1
+	}
+
+	l := strings.ToLower(t)
+	// DL is dual language, like German.DL.
+	if t == "DL" && i > 0 {
+		if p, sub, _ := languageOf(ts, i-1, true); p != "" && !sub {
+			return "mul", false, 1
+		}
+	}
+
+	if l == "dual-audio" {
+		return "mul", false, 1
+	}
+	if l == "dual" && i+1 < len(ts) && strings.EqualFold(strings.Trim(ts[i+1], " -[]()"), "audio") {
+		return "mul", false, 2
+	}
+
+	if c, ok := languageNames[l]; ok {
+		return c, false, 1
+	}
+
+	if c := subtitleLanguage(l); c != "" {
+		return c, true, 1
+	}
+
+	return "", false, 0
+}
+
+// subtitleLanguage returns the language of a lower case subtitle token, like
+// ko for korsub or fr for vostfr.
+func subtitleLanguage(t string) string {
+	switch t {
+	case "hardsub", "hardsubs", "hardcoded":
+		return "und"
+	case "esub", "esubs":
+		return "en"
+	}
+
+	var c string
+	switch {
+	case strings.HasPrefix(t, "vost"):
+		c = t[len("vost"):]
+	case strings.HasSuffix(t, "subs"):
+		c = t[:len(t)-len("subs")]
+	case strings.HasSuffix(t, "sub"):
+		c = t[:len(t)-len("sub")]
+	case strings.HasPrefix(t, "sub"):
+		c = t[len("sub"):]
+	default:
+		return ""
+	}
+	c = strings.Trim(c, ".-")
+
+	if l, ok := languageNames[c]; ok {
+		return l
+	}
+	if languageCodes[strings.ToUpper(c)] {
+		return c
+	}
+
+	return ""
+}
+
+// afterJunk reports whether token i follows a year, tech or release junk, or a
+// language.
+func afterJunk(ts []string, i int) bool {
+	if i == 0 {
+		return false
+	}
+	p := strings.Trim(ts[i-1], " -[]()")
+	if p == "" {
+		return false
+	}
+	if yearRe.FindString(p) == p || commonPatterns.match(p) || parseTech(p) != (Tech{}) {
+		return true
+	}
+	l, _, _ := languageOf(ts, i-1, true)
+	return l != ""
+}
+
+// mixedCase reports whether some of the tokens have lower case letters.
+func mixedCase(ts []string) bool {
+	for _, t := range ts {
+		if t != strings.ToUpper(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func appendLanguage(ls []string, l string) []string {
+	for _, c := range ls {
+		if c == l {
+			return ls
+		}
+	}
+	return append(ls, l)
+}
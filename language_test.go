@@ -0,0 +1,116 @@
+package plexize
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLanguages(t *testing.T) {
+	ts := []struct {
+		n      string
+		m, y   string
+		as, ss []string
+		pn     string
+	}{
+		{
+			"Amelie.2001.FRENCH.1080p.BluRay.x264",
+			"Amelie", "2001", []string{"fr"}, nil,
+			"Amelie (2001) [fr]",
+		},
+		{
+			"Das.Boot.1981.German.DL.720p.BluRay",
+			"Das Boot", "1981", []string{"de", "mul"}, nil,
+			"Das Boot (1981) [de+mul]",
+		},
+		{
+			"Movie.2019.MULTi.VOSTFR.1080p.WEB",
+			"Movie", "2019", []string{"mul"}, []string{"fr"},
+			"Movie (2019) [mul][sub-fr]",
+		},
+		{
+			"Movie 2015 Dual Audio Hindi 720p BluRay",
+			"Movie", "2015", []string{"mul", "hi"}, nil,
+			"Movie (2015) [mul+hi]",
+		},
+		{
+			"Parasite.2019.KORSUB.720p.HDRip",
+			"Parasite", "2019", nil, []string{"ko"},
+			"Parasite (2019) [sub-ko]",
+		},
+		{
+			"[@Difilm] The.Hot.Spot.1990.480p.BluRay.HardSub",
+			"The Hot Spot", "1990", nil, []string{"und"},
+			"The Hot Spot (1990) [sub-und]",
+		},
+		{
+			"Movie.2010.rus.eng.720p.CD1",
+			"Movie", "2010", []string{"ru", "en"}, nil,
+			"Movie (2010) [ru+en] - cd1",
+		},
+		{
+			"The.French.Connection",
+			"The French Connection", "", nil, nil,
+			"The French Connection",
+		},
+		{
+			"Dan.and.Fin.2019.720p",
+			"Dan And Fin", "2019", nil, nil,
+			"Dan And Fin (2019)",
+		},
+		{
+			"Per.Aspera.2019.720p",
+			"Per Aspera", "2019", nil, nil,
+			"Per Aspera (2019)",
+		},
+		{
+			"Movie.2019.720p.per.BluRay",
+			"Movie", "2019", []string{"fa"}, nil,
+			"Movie (2019) [fa]",
+		},
+		{
+			"The.English.Patient.1996.720p.BluRay",
+			"The English Patient", "1996", nil, nil,
+			"The English Patient (1996)",
+		},
+	}
+
+	for _, tt := range ts {
+		m := parse(tt.n)
+		if m.Title != tt.m {
+			t.Errorf("name: %s\ngot:  %s\nwant: %s", tt.n, m.Title, tt.m)
+		}
+		if m.Year != tt.y {
+			t.Errorf("year: %s\ngot:  %s\nwant: %s", tt.n, m.Year, tt.y)
+		}
+		if !reflect.DeepEqual(m.Languages.Audio, tt.as) {
+			t.Errorf("audio: %s\ngot:  %v\nwant: %v", tt.n, m.Languages.Audio, tt.as)
+		}
+		if !reflect.DeepEqual(m.Languages.Subtitles, tt.ss) {
+			t.Errorf("subtitles: %s\ngot:  %v\nwant: %v", tt.n, m.Languages.Subtitles, tt.ss)
+		}
+		if pn := m.NameWithLanguages(); pn != tt.pn {
+			t.Errorf("plex name: %s\ngot:  %s\nwant: %s", tt.n, pn, tt.pn)
+		}
+	}
+}
+
+func TestParseLanguagesEpisode(t *testing.T) {
+	ts := []struct {
+		n, en string
+		as    []string
+	}{
+		{"Seinfeld.S03E10.The.Chinese.Restaurant", "The Chinese Restaurant", nil},
+		{"Show.S01E02.The.French.Mistake.720p", "The French Mistake", nil},
+		{"Show.S01E02.Pilot.FRENCH.720p", "Pilot", []string{"fr"}},
+	}
+
+	for _, tt := range ts {
+		m := parse(tt.n)
+		if m.EpisodeTitle != tt.en {
+			t.Errorf("episode name: %s\ngot:  %s\nwant: %s", tt.n, m.EpisodeTitle, tt.en)
+		}
+		if !reflect.DeepEqual(m.Languages.Audio, tt.as) {
+			t.Errorf("audio: %s\ngot:  %v\nwant: %v", tt.n, m.Languages.Audio, tt.as)
+		}
+	}
+}
@@ -1,38 +1,57 @@
-package main
+// Package plexize parses movie and TV show file names and builds Plex friendly
+// names out of them.
+package plexize
 
 import (
-	"bufio"
-	"flag"
+	"errors"
 	"fmt"
-	"log"
-	"os"
-	"os/user"
 	"path/filepath"
 	"regexp"
-	"runtime"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
-var uid int = -1
-var gid int
+// ErrNoTitle is returned by Parse when no title can be found in the file name.
+var ErrNoTitle = errors.New("plexize: no title found")
+
+// dateMark replaces the air date in the name before splitting it to tokens.
+const dateMark = "\x00"
+
+// editions maps lower case edition tokens, single or paired, to Plex edition names.
+var editions = map[string]string{
+	"extended":         "Extended",
+	"extended cut":     "Extended",
+	"extended edition": "Extended",
+	"extendedcut":      "Extended",
+	"directors cut":    "Director's Cut",
+	"director's cut":   "Director's Cut",
+	"directorscut":     "Director's Cut",
+	"theatrical":       "Theatrical",
+	"theatrical cut":   "Theatrical",
+	"remastered":       "Remastered",
+	"imax":             "IMAX",
+	"unrated":          "Unrated",
+	"unrated cut":      "Unrated",
+	"final cut":        "Final Cut",
+	"special edition":  "Special Edition",
+}
 
 var toRemove = [...]string{"unknown_release_type", "filmpokvip", "Film_pok"}
 
-func init() {
-	u, _ := user.Lookup("plex")
-	if u == nil {
-		return
-	}
-	var err error
-	uid, err = strconv.Atoi(u.Uid)
-	if err != nil {
-		log.Panicf("cannot parse the number: %v\n", err)
-	}
-	gid, err = strconv.Atoi(u.Gid)
-	if err != nil {
-		log.Panicf("cannot parse the number: %v\n", err)
-	}
+// Parser parses names with extra junk words, like site tags, removed before
+// parsing. A nil Parser removes only the built-in junk words.
+type Parser struct {
+	junk []string
+}
+
+// NewParser returns a parser which removes junk words from names, besides the
+// built-in ones.
+func NewParser(junk []string) *Parser {
+	return &Parser{junk: slices.Clone(junk)}
 }
 
 type patterns [11]*regexp.Regexp
@@ -61,69 +80,377 @@ var commonPatterns = patterns{
 	regexp.MustCompile(`UpScaled|iNTERNAL|CONVERT|READNFO|PROPER|REPACK|UNRATED|FRENCH|(?:(?i)hardsub)|(?:(?i)ensub)|(?:(?i)rarbg)|(?:(?i)hevc)|AMZN|PDTV|YIFY|1CD|WEB|NBY|R[0-9]|TS|HC|WS|3D`),
 }
 
-type movie struct {
-	sep     string
-	name    string
-	year    string
-	season  string
-	episode string
-	epiName string
+var (
+	// First movie ever was in 1888, so lets check movie years since 1800.
+	yearRe        = regexp.MustCompile(`((?:1[8-9]|[2-9]\d)\d{2})`)
+	seasonRe      = regexp.MustCompile(`[sS]?(\d{1,2})[eExX](\d{1,2})((?:-?(?:[sS]?\d{1,2})?[eExX]\d{1,2}|-\d{1,2}\b)*)`)
+	episodeRe     = regexp.MustCompile(`(-?)(?:[sS]?\d{1,2}[eExX]|[eExX])?(\d{1,2})`)
+	bracketRe     = regexp.MustCompile(`\[([^\]]*)\]`)
+	crcRe         = regexp.MustCompile(`^[0-9A-Fa-f]{8}$`)
+	animeRe       = regexp.MustCompile(`^(.+?)(?: \(((?:1[8-9]|[2-9]\d)\d{2})\))?(?: [sS](\d{1,2}))? - (\d{1,4})(?:[vV](\d))?(?: - (.+?))?(?: \([^)]*\))*$`)
+	specialRe     = regexp.MustCompile(`^(?i)(?:sp(\d{1,3})|(?:ova|oad|specials?)(\d{1,3})?)$`)
+	numberRe      = regexp.MustCompile(`^\d{1,3}$`)
+	partRe        = regexp.MustCompile(`^(?i)(cd|dis[ck]|dvd|part|pt)(\d{1,2})?$`)
+	taggedIDRe    = regexp.MustCompile(`(?i)[{\[(]?\b(imdb|tmdb|tvdb)(?:id)?[-=](tt\d{7,8}|\d+)\b[}\])]?`)
+	imdbRe        = regexp.MustCompile(`[{\[(]?\btt\d{7,8}\b[}\])]?`)
+	packRe        = regexp.MustCompile(`^(.*?)[ ._\-]+(?:[sS](\d{1,2})|[sS]eason[ ._\-]?(\d{1,2}))(?:[ ._\-]|$)`)
+	bareEpisodeRe = regexp.MustCompile(`^(?:[eE](?:[pP](?:isode)?)?[ ._\-]?)?(\d{1,3})(?:[ ._\-]+(.+))?$`)
+	dateRe        = regexp.MustCompile(`(?:^|[ ._\-])(((?:19|20)\d{2})[ ._\-](0[1-9]|1[0-2])[ ._\-](0[1-9]|[12]\d|3[01])|(0[1-9]|[12]\d|3[01])[ ._\-](0[1-9]|1[0-2])[ ._\-]((?:19|20)\d{2}))(?:$|[ ._\-])`)
+	domainRe      = regexp.MustCompile(`^[wW]{2,3}\.[^.]*\.[^.]{3,4}(.*)$`)
+	bracePrefixRe = regexp.MustCompile(`^[\[\(🃏].*[\]\)🃏](.*)$`)
+	prefixRe      = regexp.MustCompile(`^[^0-9a-zA-Z]*(.*)$`)
+)
+
+// Media is a parsed movie or TV show file.
+type Media struct {
+	Title        string
+	Year         string
+	Season       string
+	Episode      string   // Absolute episode number if Season is empty.
+	Episodes     []string // All episodes of a multi-episode file, empty otherwise.
+	Date         string   // Air date (YYYY-MM-DD) of a date-based episode, Season is set to its year.
+	EpisodeTitle string
+	Revision     string // Anime release revision, like 2 for v2.
+	CRC          string // Anime release CRC32 tag.
+	Part         string // Stacked part of a multi-part file, like cd1 or pt2.
+	Edition      string // Movie edition, like Extended or Director's Cut.
+	IDs          IDs
+	Tech         Tech
+	Release      Release
+	Languages    Languages
+	Ext          string
+}
+
+// IDs are external database IDs of a movie or TV show.
+type IDs struct {
+	IMDb string // Like tt1234567.
+	TMDb string
+	TVDb string
+}
+
+// SetID sets an external ID, like tt1234567, imdb-tt1234567, tmdb-123 or
+// tvdb-123.
+func (m *Media) SetID(id string) error {
+	ids, rest := cutIDs(id)
+	if ids == (IDs{}) || strings.Trim(rest, " {}[]()") != "" {
+		return fmt.Errorf("plexize: invalid id %q", id)
+	}
+
+	if ids.IMDb != "" {
+		m.IDs.IMDb = ids.IMDb
+	}
+	if ids.TMDb != "" {
+		m.IDs.TMDb = ids.TMDb
+	}
+	if ids.TVDb != "" {
+		m.IDs.TVDb = ids.TVDb
+	}
+
+	return nil
+}
+
+// IsEpisode reports whether the media is a TV show episode.
+func (m Media) IsEpisode() bool {
+	return m.Season != "" || m.Episode != ""
+}
+
+// Parse parses a movie or TV show file name. Directory part of the name is
+// ignored, and the extension is kept in lower case.
+func Parse(filename string) (Media, error) {
+	return (*Parser)(nil).Parse(filename)
 }
 
-type plexFile struct {
-	dir  string
-	name string
-	ext  string
-	mov  movie
+// Parse is like the package Parse, removing the junk words of the parser.
+func (p *Parser) Parse(filename string) (Media, error) {
+	_, file := filepath.Split(filename)
+	ext := filepath.Ext(file)
+
+	m := p.parse(strings.TrimSuffix(file, ext))
+	m.Ext = strings.ToLower(ext)
+	if m.Title == "" {
+		return m, ErrNoTitle
+	}
+
+	return m, nil
 }
 
-func (p *plexFile) parse() {
-	var (
-		// First movie ever was in 1888, so lets check movie years since 1800.
-		yearRe        = regexp.MustCompile(`((?:1[8-9]|[2-9]\d)\d{2})`)
-		seasonRe      = regexp.MustCompile(`[sS]?(\d{1,2})[eExX](\d{1,2})`)
-		domainRe      = regexp.MustCompile(`^[wW]{2,3}\.[^.]*\.[^.]{3,4}(.*)$`)
-		bracePrefixRe = regexp.MustCompile(`^[\[\(🃏].*[\]\)🃏](.*)$`)
-		prefixRe      = regexp.MustCompile(`^[^0-9a-zA-Z]*(.*)$`)
-	)
+// ParseDir parses a season pack or TV show folder name, like
+// Breaking.Bad.S03.1080p.BluRay, to be used as context by ParseIn.
+func ParseDir(dirname string) (Media, error) {
+	return (*Parser)(nil).ParseDir(dirname)
+}
+
+// ParseDir is like the package ParseDir, removing the junk words of the parser.
+func (p *Parser) ParseDir(dirname string) (Media, error) {
+	n := filepath.Base(filepath.Clean(dirname))
+
+	var m Media
+	if pk := packRe.FindStringSubmatch(n); pk != nil {
+		m = p.parse(pk[1])
+		m.Season = fmt.Sprintf("%02v", pk[2]+pk[3])
+	} else {
+		m = p.parse(n)
+	}
+	if m.Title == "" {
+		return m, ErrNoTitle
+	}
+
+	return m, nil
+}
+
+// ParseIn parses a file name inside a folder parsed by ParseDir. If the file is
+// an episode with a weak title (empty, numeric or abbreviated), the title and
+// year of the folder is used. In a season folder, bare episode numbers like
+// "05 - Title" or "Episode 5" are recognized too.
+func ParseIn(filename string, dir Media) (Media, error) {
+	return (*Parser)(nil).ParseIn(filename, dir)
+}
 
-	n := p.name
+// ParseIn is like the package ParseIn, removing the junk words of the parser.
+func (p *Parser) ParseIn(filename string, dir Media) (Media, error) {
+	m, err := p.Parse(filename)
+	if dir.Title == "" {
+		return m, err
+	}
+
+	if !m.IsEpisode() && dir.Season != "" {
+		_, file := filepath.Split(filename)
+		if e := bareEpisodeRe.FindStringSubmatch(strings.TrimSuffix(file, filepath.Ext(file))); e != nil {
+			m = Media{
+				Season:       dir.Season,
+				Episode:      fmt.Sprintf("%02v", e[1]),
+				EpisodeTitle: p.parse(e[2]).Title,
+				Ext:          m.Ext,
+			}
+		}
+	}
+
+	if m.IsEpisode() && weakTitle(m.Title, dir.Title) {
+		m.Title = dir.Title
+		if m.Year == "" {
+			m.Year = dir.Year
+		}
+		if m.IDs == (IDs{}) {
+			m.IDs = dir.IDs
+		}
+	}
+	if m.Title == "" {
+		return m, ErrNoTitle
+	}
+
+	return m, nil
+}
 
+// weakTitle reports whether the title t is too poor to be trusted over the
+// show title, like an empty, numeric, or abbreviated (Bb for Breaking Bad) one.
+func weakTitle(t, show string) bool {
+	if strings.IndexFunc(t, unicode.IsLetter) == -1 {
+		return true
+	}
+
+	var in strings.Builder
+	for _, w := range strings.Fields(show) {
+		r, _ := utf8.DecodeRuneInString(w)
+		in.WriteRune(r)
+	}
+
+	return strings.EqualFold(strings.ReplaceAll(t, " ", ""), in.String())
+}
+
+func parse(n string) Media {
+	return (*Parser)(nil).parse(n)
+}
+
+func (p *Parser) parse(n string) Media {
 	for _, s := range toRemove {
 		n = strings.ReplaceAll(n, s, "")
 	}
+	if p != nil {
+		for _, s := range p.junk {
+			n = strings.ReplaceAll(n, s, "")
+		}
+	}
+
+	ids, n := cutIDs(n)
+
+	m, ok := parseAnime(n)
+	if ok {
+		m.Tech = parseTech(cutTitle(n, m.Title))
+	} else {
+		var r Release
+		var rest string
+		r, n = cutRelease(n)
+		m, rest = parseName(n)
+		m.Release.Group, m.Release.Tags = r.Group, r.Tags
+		m.Tech = parseTech(rest)
+	}
+	m.IDs = ids
+
+	return m
+}
+
+// cutTitle cuts the first occurrence of a title out of a name, ignoring case
+// and separators, like "Sword Art Online" out of "[Group] Sword_Art_Online - 05".
+func cutTitle(n, title string) string {
+	ts := strings.Fields(title)
+	if len(ts) == 0 {
+		return n
+	}
+	for i := range ts {
+		ts[i] = regexp.QuoteMeta(ts[i])
+	}
+	re, err := regexp.Compile(`(?i)` + strings.Join(ts, `[ ._\-]+`))
+	if err != nil {
+		return n
+	}
+	if l := re.FindStringIndex(n); l != nil {
+		return n[:l[0]] + n[l[1]:]
+	}
+	return n
+}
+
+// cutIDs cuts external IDs out of the name, like {imdb-tt1234567}, [tmdbid-123]
+// or a bare IMDb ID.
+func cutIDs(n string) (IDs, string) {
+	var ids IDs
+
+	for _, id := range taggedIDRe.FindAllStringSubmatch(n, -1) {
+		switch strings.ToLower(id[1]) {
+		case "imdb":
+			ids.IMDb = id[2]
+		case "tmdb":
+			ids.TMDb = id[2]
+		case "tvdb":
+			ids.TVDb = id[2]
+		}
+	}
+	n = taggedIDRe.ReplaceAllString(n, "")
+
+	if id := imdbRe.FindString(n); id != "" {
+		ids.IMDb = id
+		n = imdbRe.ReplaceAllString(n, "")
+	}
+
+	return ids, n
+}
+
+// parseName parses a name without release data, and returns the media with the
+// rest of the name which is not the title, to parse its tech attributes.
+func parseName(n string) (Media, string) {
+	var m Media
 
 	n = bracePrefixRe.ReplaceAllString(n, "$1")
 	n = domainRe.ReplaceAllString(n, "$1")
 	n = prefixRe.ReplaceAllString(n, "$1")
+
+	if d, r := airDate(n); d != "" {
+		m.Date = d
+		m.Season = m.Date[:4]
+		n = r
+	}
 	n = strings.ReplaceAll(n, " - ", " ")
 
+	sep := ""
 	max := 0
 	for _, s := range [...]string{" ", ".", "-", "_"} {
 		c := strings.Count(n, s)
 		if c > max {
 			max = c
-			p.mov.sep = s
+			sep = s
 			continue
 		}
 	}
 
-	if p.mov.sep == "" {
-		p.mov.name = strings.Title(n)
-		return
+	if sep == "" {
+		if s := seasonRe.FindStringSubmatch(n); len(s) != 0 && s[0] == n {
+			m.setEpisodes(s)
+			return m, n
+		}
+		m.Title = strings.Title(n)
+		return m, ""
 	}
 
-	ts := strings.Split(n, p.mov.sep)
+	ts := strings.Split(n, sep)
+	// Tokens of the title, which are not tech attributes, like DV in "The DV Files".
+	title := make([]bool, len(ts))
 	done := false
 	seasoned := false
-	for _, t := range ts {
-		t = strings.Trim(t, " -[]()")
+	special := false
+	junk := false
+	for i := 0; i < len(ts); i++ {
+		t := strings.Trim(ts[i], " -[]()")
+		if t == "" {
+			continue
+		}
+
+		if t == dateMark {
+			done = true
+			seasoned = true
+			continue
+		}
+
+		if special {
+			special = false
+			if m.Episode == "" && numberRe.MatchString(t) {
+				m.Episode = fmt.Sprintf("%02v", t)
+				continue
+			}
+		}
+
+		if p, n := partOf(ts, i, done); p != "" && m.Title != "" {
+			done = true
+			m.Part = p
+			i += n - 1
+			continue
+		}
+
+		if e, n := editionOf(ts, i, done); e != "" && m.Title != "" {
+			done = true
+			if m.Edition == "" {
+				m.Edition = e
+			} else if !strings.Contains(m.Edition, e) {
+				m.Edition += " " + e
+			}
+			i += n - 1
+			continue
+		}
+
+		if l, sub, n := languageOf(ts, i, done); l != "" && m.Title != "" {
+			done = true
+			if sub {
+				m.Languages.Subtitles = appendLanguage(m.Languages.Subtitles, l)
+			} else {
+				m.Languages.Audio = appendLanguage(m.Languages.Audio, l)
+			}
+			i += n - 1
+			continue
+		}
+
+		if done && m.Release.releaseFlag(ts, i) {
+			continue
+		}
+
+		if junk {
+			continue
+		}
+
+		if sp := specialRe.FindStringSubmatch(t); len(sp) != 0 && m.Title != "" && !seasoned && m.Year == "" && !specialEdition(ts, i) && !numberedAfter(ts, i) {
+			done = true
+			seasoned = true
+			special = true
+			m.Season = "00"
+			if e := sp[1] + sp[2]; e != "" {
+				m.Episode = fmt.Sprintf("%02v", e)
+			}
+			continue
+		}
+
 		if !done {
-			if y := yearRe.FindString(t); y != "" && p.mov.name != "" {
+			if y := yearRe.FindString(t); y != "" && m.Title != "" {
 				done = true
-				p.mov.year = y
+				m.Year = y
 				if i := strings.Index(t, y); i > 0 {
-					p.mov.name += t[:i-1] + " "
+					m.Title += t[:i-1] + " "
 				}
 				continue
 			}
@@ -131,8 +458,7 @@ func (p *plexFile) parse() {
 			if s := seasonRe.FindStringSubmatch(t); len(s) != 0 {
 				done = true
 				seasoned = true
-				p.mov.season = fmt.Sprintf("%02v", s[1])
-				p.mov.episode = fmt.Sprintf("%02v", s[2])
+				m.setEpisodes(s)
 				continue
 			}
 
@@ -141,261 +467,344 @@ func (p *plexFile) parse() {
 				continue
 			}
 
-			p.mov.name += t + " "
+			m.Title += t + " "
+			title[i] = true
 			continue
 		}
 
 		if y := yearRe.FindString(t); y != "" {
-			if p.mov.year == "" {
-				p.mov.year = y
+			if m.Year == "" {
+				m.Year = y
 			} else {
-				p.mov.name += p.mov.year + " "
-				p.mov.year = y
+				m.Title += m.Year + " "
+				m.Year = y
 			}
 			continue
 		}
 
 		if s := seasonRe.FindStringSubmatch(t); len(s) != 0 {
 			seasoned = true
-			p.mov.season = fmt.Sprintf("%02v", s[1])
-			p.mov.episode = fmt.Sprintf("%02v", s[2])
+			m.setEpisodes(s)
 			continue
 		}
 
 		if commonPatterns.match(t) {
-			break
+			// Only stacked parts could come after junk.
+			junk = true
+			continue
+		}
+
+		// Tech attributes after the title, like UHD or Atmos, are not words.
+		if parseTech(t) != (Tech{}) {
+			continue
 		}
 
 		if seasoned {
-			p.mov.epiName += t + " "
+			m.EpisodeTitle += t + " "
 		} else {
-			p.mov.name += t + " "
+			m.Title += t + " "
+			title[i] = true
 		}
 	}
 
-	p.mov.name = strings.TrimSpace(p.mov.name)
-	p.mov.name = strings.Title(p.mov.name)
+	// SxxEyy numbering wins over an air date, not to mix the season of one with
+	// the date of another.
+	if m.Date != "" && m.Episode != "" {
+		m.Date = ""
+	}
+
+	m.Title = strings.TrimSpace(m.Title)
+	m.Title = strings.Title(m.Title)
 
 	if seasoned {
-		p.mov.epiName = strings.TrimSpace(p.mov.epiName)
-		p.mov.epiName = strings.Title(p.mov.epiName)
+		m.EpisodeTitle = strings.TrimSpace(m.EpisodeTitle)
+		m.EpisodeTitle = strings.Title(m.EpisodeTitle)
 	}
-}
 
-func (p *plexFile) plexName() string {
-	if p.mov.name == "" {
-		return ""
+	var rest []string
+	for i, t := range ts {
+		if !title[i] {
+			rest = append(rest, t)
+		}
 	}
 
-	if p.mov.season == "" {
-		if p.mov.year == "" {
-			return p.mov.name
+	return m, strings.Join(rest, sep)
+}
+
+// airDate returns the air date of a name in YYYY-MM-DD form, and the name with
+// the date replaced by dateMark, or empty if there is no date. Dates must be
+// valid, and DD.MM.YYYY dates, which are ambiguous with titles like "Oceans 11",
+// need a zero-padded day or month, like 07.03.2019, or a " - " before them.
+func airDate(n string) (string, string) {
+	for _, d := range dateRe.FindAllStringSubmatchIndex(n, -1) {
+		ds := func(i int) string {
+			if d[2*i] < 0 {
+				return ""
+			}
+			return n[d[2*i]:d[2*i+1]]
 		}
-		return fmt.Sprintf("%s (%s)", p.mov.name, p.mov.year)
+		var date string
+		if ds(2) != "" {
+			date = ds(2) + "-" + ds(3) + "-" + ds(4)
+		} else {
+			if ds(5)[0] != '0' && ds(6)[0] != '0' && !strings.HasSuffix(n[:d[2]], " - ") {
+				continue
+			}
+			date = ds(7) + "-" + ds(6) + "-" + ds(5)
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			continue
+		}
+		return date, n[:d[2]] + dateMark + n[d[3]:]
 	}
+	return "", n
+}
 
-	if p.mov.epiName == "" {
-		if p.mov.year == "" {
-			return fmt.Sprintf("%s - s%se%s", p.mov.name, p.mov.season, p.mov.episode)
-		}
-		return fmt.Sprintf("%s (%s) - s%se%s", p.mov.name, p.mov.year, p.mov.season, p.mov.episode)
+// partOf returns the stacked part at token i, like cd1 from CD1 or part2 from
+// "Part 2", and the number of tokens used. Before the end of the title, only
+// unambiguous cd, disc and pt parts are recognized, so titles like "Deathly
+// Hallows Part 1" are kept.
+func partOf(ts []string, i int, done bool) (string, int) {
+	p := partRe.FindStringSubmatch(strings.Trim(ts[i], " -[]()"))
+	if len(p) == 0 {
+		return "", 0
 	}
 
-	if p.mov.year == "" {
-		return fmt.Sprintf("%s - s%se%s - %s", p.mov.name, p.mov.season, p.mov.episode, p.mov.epiName)
+	k := strings.ToLower(p[1])
+	if !done && k != "cd" && k != "disc" && k != "disk" && k != "pt" {
+		return "", 0
 	}
 
-	return fmt.Sprintf("%s (%s) - s%se%s - %s", p.mov.name, p.mov.year, p.mov.season, p.mov.episode, p.mov.epiName)
+	if p[2] != "" {
+		n, _ := strconv.Atoi(p[2])
+		return fmt.Sprintf("%s%d", k, n), 1
+	}
+
+	if !done || i+1 == len(ts) {
+		return "", 0
+	}
+	t := strings.Trim(ts[i+1], " -[]()")
+	if !numberRe.MatchString(t) {
+		return "", 0
+	}
+	n, _ := strconv.Atoi(t)
+
+	return fmt.Sprintf("%s%d", k, n), 2
 }
 
-func (p *plexFile) plexDir() string {
-	if p.mov.name == "" {
-		return ""
+// editionOf returns the edition at token i, like Director's Cut from
+// "Directors Cut", and the number of tokens used. Before the end of the title,
+// only Extended and Unrated editions are recognized, which were junk anyway.
+func editionOf(ts []string, i int, done bool) (string, int) {
+	t := strings.ToLower(strings.Trim(ts[i], " -[]()"))
+	if !done && editions[t] != "Extended" && editions[t] != "Unrated" {
+		return "", 0
+	}
+
+	if i+1 < len(ts) {
+		if e, ok := editions[t+" "+strings.ToLower(strings.Trim(ts[i+1], " -[]()"))]; ok {
+			return e, 2
+		}
 	}
 
-	if p.mov.year == "" {
-		return p.mov.name
+	if e, ok := editions[t]; ok {
+		return e, 1
 	}
 
-	return fmt.Sprintf("%s (%s)", p.mov.name, p.mov.year)
+	return "", 0
 }
 
-func (p *plexFile) seasonDir() string {
-	if p.mov.season == "" {
-		return ""
+// specialEdition reports whether the special token i is a part of a "Special
+// Edition" or "Special Features" rather than a TV show special.
+func specialEdition(ts []string, i int) bool {
+	if i+1 >= len(ts) {
+		return false
+	}
+	switch strings.ToLower(strings.Trim(ts[i+1], " -[]()")) {
+	case "edition", "features":
+		return true
 	}
+	return false
+}
 
-	return fmt.Sprintf("Season %s", p.mov.season)
+// numberedAfter reports whether a year or SxxEyy comes after token i, so a
+// special word before it, like in "The Special Relationship 2010" or "Very
+// Special Episode S01E01", is a part of the title.
+func numberedAfter(ts []string, i int) bool {
+	for _, t := range ts[i+1:] {
+		t = strings.Trim(t, " -[]()")
+		if t != "" && yearRe.FindString(t) == t || seasonRe.MatchString(t) {
+			return true
+		}
+	}
+	return false
 }
 
-func usage() {
-	fmt.Fprintln(flag.CommandLine.Output(), `Movie and TV show files, Plex friendly maker.
+// parseAnime parses fansub anime names like "[Group] Title - 05v2 [1080p][CRC32]",
+// where episode numbers are absolute. It reports false if the name does not
+// look like an anime release.
+func parseAnime(n string) (Media, bool) {
+	var m Media
 
-Usage:
-  plexize [-]
-  plexize [OPTION]... FILE...
+	bs := bracketRe.FindAllStringSubmatch(n, -1)
+	if len(bs) == 0 {
+		return m, false
+	}
 
-Options:
-  -d, --dry-run             Show result without running
-  -m, --change-mode         Change file mode to 660
-  -o, --change-owner        Change file owner to plex:plex (sudo might be needed)
-  -p, --path PATH           Output path (move file to the path and then refactor)
-  -s, --separate            Separate movie files in their own folders (not required for TV series)
-  -n, --rename              Rename the parsed plex directory (good for TV series)
+	r := strings.TrimSpace(bracketRe.ReplaceAllString(n, " "))
+	if !strings.Contains(r, " ") {
+		r = strings.ReplaceAll(r, "_", " ")
+	}
+	r = strings.Join(strings.Fields(r), " ")
 
-Example:
-  $ plexize                                        # start in interactive mode to convert file(s) name
-  $ cat movie_list.txt | plexize                   # convert file(s) name with piping
-  $ plexize trainwreck.mkv war.dogs.2016.mkv       # convert multiple files
-  $ plexize the*.mkv                               # convert multiple files with wildcard
-  $ plexize -d The.Platform.2019.720p.mkv          # dry run
-  $ plexize -p ~/plex The.Platform.2019.720p.mkv   # move the file to ~/plex and convert
-  $ plexize -m -o -s The.Platform.2019.720p.mkv    # change mode/owner and move the movie file to its own folder
-  $ plexize -m -o The.Flash.2014.S01E01.HDTV.mkv   # change mode/owner a TV show file (would be separated in its own folder)
-  $ plexize -m -o -r dc-flash The.Flash.S01E01.mkv # change mode/owner and rename the TV show folder`)
-}
+	a := animeRe.FindStringSubmatch(r)
+	if a == nil {
+		return m, false
+	}
 
-func main() {
-	log.SetFlags(0)
+	m.Title = strings.Title(a[1])
+	m.Year = a[2]
+	if a[3] != "" {
+		m.Season = fmt.Sprintf("%02v", a[3])
+	}
+	if i := strings.LastIndex(m.Title, " "); i > 0 && specialRe.MatchString(m.Title[i+1:]) {
+		m.Title = m.Title[:i]
+		m.Season = "00"
+	}
+	m.Episode = fmt.Sprintf("%02v", a[4])
+	m.Revision = a[5]
+	m.EpisodeTitle = strings.Title(a[6])
+	if strings.HasPrefix(n, "[") {
+		m.Release.Group = bs[0][1]
+	}
+	for _, b := range bs {
+		if crcRe.MatchString(b[1]) {
+			m.CRC = strings.ToUpper(b[1])
+		}
+	}
 
-	var (
-		dryRun, chmod, chown, separate bool
-		outDir, renameDir              string
-	)
+	return m, true
+}
 
-	flag.Usage = usage
-	flag.BoolVar(&dryRun, "d", false, "Show result without running")
-	flag.BoolVar(&dryRun, "dry-run", false, "Show result without running")
-	flag.BoolVar(&chmod, "m", false, "Change file mode to 660")
-	flag.BoolVar(&chmod, "change-mode", false, "Change file mode to 660")
-	flag.BoolVar(&chown, "o", false, "Change file owner (default is plex:plex)")
-	flag.BoolVar(&chown, "change-owner", false, "Change file owner (default is plex:plex)")
-	flag.StringVar(&outDir, "p", "", "Output path (move file to the path and then refactor)")
-	flag.StringVar(&outDir, "path", "", "Output path (move file to the path and then refactor)")
-	flag.BoolVar(&separate, "s", false, "Separate movie files in their own folders (not required for TV series)")
-	flag.BoolVar(&separate, "separate", false, "Separate movie files in their own folders (not required for TV series)")
-	flag.StringVar(&renameDir, "r", "", "Rename the parsed plex directory (good for TV series)")
-	flag.StringVar(&renameDir, "rename", "", "Rename the parsed plex directory (good for TV series)")
-	flag.Parse()
+// setEpisodes sets season and episode(s) from a seasonRe submatch. Chained
+// episodes (S01E01E02) are listed one by one, and ranges (S01E01-E03,
+// 1x01-1x03) are expanded.
+func (m *Media) setEpisodes(s []string) {
+	m.Season = fmt.Sprintf("%02v", s[1])
+	m.Episode = fmt.Sprintf("%02v", s[2])
+	m.Episodes = nil
+	if s[3] == "" {
+		return
+	}
 
-	if flag.Arg(0) == "" || flag.Arg(0) == "-" {
-		scanner := bufio.NewScanner(os.Stdin)
-		for scanner.Scan() {
-			l := scanner.Text()
-			log.Printf("%s\n", convert(l, true, false, false, "", ""))
+	last, _ := strconv.Atoi(s[2])
+	es := []string{m.Episode}
+	for _, e := range episodeRe.FindAllStringSubmatch(s[3], -1) {
+		n, _ := strconv.Atoi(e[2])
+		if n <= last {
+			continue
+		}
+		if e[1] == "" {
+			last = n - 1
 		}
-		if err := scanner.Err(); err != nil {
-			log.Fatalf("cannot read from stdin: %v\n", err)
+		for last < n {
+			last++
+			es = append(es, fmt.Sprintf("%02d", last))
 		}
-		os.Exit(0)
 	}
+	if len(es) > 1 {
+		m.Episodes = es
+	}
+}
 
-	if dryRun {
-		log.Println("Dry run...")
+// episode returns the Plex episode part of the name, like s01e02, s01e02-e04
+// for multi-episode files, e05 for absolute numbered episodes, the air date for
+// date-based episodes, or s00 for specials with unknown episode number.
+func (m Media) episode() string {
+	if m.Date != "" {
+		return m.Date
 	}
 
-	if chown {
-		if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
-			chown = false
-			log.Println("the OS does not support changing the file owner")
-		} else if uid == -1 {
-			chown = false
-			log.Println("user plex does not exist, cannot change the file owner")
-		}
+	if m.Season == "" {
+		return fmt.Sprintf("e%s", m.Episode)
 	}
 
-	for i := 0; i < flag.NArg(); i++ {
-		var paths []string
-		var err error
-		if strings.Contains(flag.Arg(i), "*") {
-			paths, err = filepath.Glob(flag.Arg(i))
-			if err != nil {
-				log.Fatalf("invalid path format: %v\n", err)
-			}
-		} else {
-			paths = append(paths, flag.Arg(i))
-		}
-		for _, path := range paths {
-			np := convert(path, dryRun, separate, chown, outDir, renameDir)
-			log.Printf("%s -> %s\n", path, np)
-
-			if !dryRun {
-				err := os.Rename(path, np)
-				if err != nil {
-					if os.IsPermission(err) {
-						log.Printf("you don't have permission to move/rename the file (you can retry with sudo): %v\n", err)
-					} else {
-						log.Printf("cannot move/rename the file: %v\n", err)
-					}
-				}
+	if m.Episode == "" {
+		return fmt.Sprintf("s%s", m.Season)
+	}
 
-				if chmod {
-					err := os.Chmod(np, 0660)
-					if err != nil {
-						log.Printf("cannot change the file mode: %v\n", err)
-					}
-				}
+	if len(m.Episodes) > 1 {
+		return fmt.Sprintf("s%se%s-e%s", m.Season, m.Episode, m.Episodes[len(m.Episodes)-1])
+	}
 
-				if chown {
-					err = os.Chown(np, uid, gid)
-					if os.IsPermission(err) {
-						log.Printf("you don't have permission to change owner of the file (you can retry with sudo): %v\n", err)
-					}
-				}
+	return fmt.Sprintf("s%se%s", m.Season, m.Episode)
+}
 
-				// TODO: support copy to server (delete local).
-				// TODO: support fixing title in metadata.
-			}
-		}
-	}
+// Name returns the Plex file name of the media, without extension.
+func (m Media) Name() string {
+	n, _ := defaultNamer.name(m, false)
+	return n
 }
 
-func convert(path string, dryRun, separate, chown bool, outDir string, renameDir string) (newPath string) {
-	dir, file := filepath.Split(path)
-	ext := filepath.Ext(file)
+// NameWithLanguages returns the Plex file name of the media like Name, with
+// audio and subtitle languages, like "Amelie (2001) [fr][sub-en]".
+func (m Media) NameWithLanguages() string {
+	n, _ := defaultNamer.name(m, true)
+	return n
+}
 
-	pf := &plexFile{
-		dir:  dir,
-		name: strings.TrimSuffix(file, ext),
-		ext:  strings.ToLower(ext),
-		mov:  movie{},
-	}
-	pf.parse()
+// Dir returns the Plex movie or TV show folder name of the media, with an
+// external ID tag if any.
+func (m Media) Dir() string {
+	d, _ := defaultNamer.dir(m)
+	return d
+}
 
-	ps := make([]string, 0, 4)
-	ps = append(ps, dir)
-	if outDir != "" {
-		ps[0] = outDir
+// idTag returns an external ID of the media in Plex tag syntax, like
+// {imdb-tt1234567}. TVDb is preferred for TV shows, and IMDb for movies.
+func (m Media) idTag() string {
+	if src, id := m.id(); id != "" {
+		return fmt.Sprintf("{%s-%s}", src, id)
 	}
-	if separate || pf.mov.season != "" {
-		if renameDir != "" {
-			ps = append(ps, renameDir)
-		} else {
-			ps = append(ps, pf.plexDir())
-		}
-		if !dryRun {
-			makeDir(chown, "cannot make separate movie or TV series folder: %v\n", ps...)
-		}
+
+	return ""
+}
+
+// id returns the preferred external ID of the media with its source, like
+// imdb and tt1234567.
+func (m Media) id() (src, id string) {
+	ids := [...][2]string{{"imdb", m.IDs.IMDb}, {"tmdb", m.IDs.TMDb}, {"tvdb", m.IDs.TVDb}}
+	if m.IsEpisode() {
+		ids[0], ids[2] = ids[2], ids[0]
 	}
-	if pf.mov.season != "" {
-		ps = append(ps, pf.seasonDir())
-		if !dryRun {
-			makeDir(chown, "cannot make TV series season folder: %v\n", ps...)
+
+	for _, id := range ids {
+		if id[1] != "" {
+			return id[0], id[1]
 		}
 	}
-	ps = append(ps, pf.plexName())
-	return fmt.Sprintf("%s%s", filepath.Join(ps...), pf.ext)
+
+	return "", ""
 }
 
-func makeDir(chown bool, errMsg string, ps ...string) {
-	err := os.MkdirAll(filepath.Join(ps...), os.ModePerm)
-	if err != nil && !os.IsExist(err) {
-		log.Printf(errMsg, err)
+// title returns the title and year of the media, with the edition of movies in
+// Plex edition syntax, like "Blade Runner (1982) {edition-Final Cut}".
+func (m Media) title() string {
+	t := m.Title
+	if m.Year != "" {
+		t = fmt.Sprintf("%s (%s)", t, m.Year)
 	}
-	if chown {
-		err = os.Chown(filepath.Join(ps...), uid, gid)
-		if os.IsPermission(err) {
-			log.Printf("you don't have permission to change owner of the file (you can retry with sudo): %v\n", err)
-		}
+
+	if m.Edition != "" && !m.IsEpisode() {
+		t = fmt.Sprintf("%s {edition-%s}", t, m.Edition)
 	}
+
+	return t
+}
+
+// SeasonDir returns the Plex season folder name of the media, or empty if the
+// media is not a TV show episode. Season zero goes to Specials folder.
+func (m Media) SeasonDir() string {
+	d, _ := defaultNamer.seasonDir(m)
+	return d
 }
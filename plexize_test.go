@@ -1,8 +1,8 @@
-package main
+package plexize
 
 import (
-	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -10,571 +10,1061 @@ func TestParse(t *testing.T) {
 	ts := []struct {
 		n              string
 		m, y, s, e, en string
+		es             []string
 	}{
 		{
 			"[ www.Speed.cd ] -Sons.of.Anarchy.S07E07.720p.HDTV.X264-DIMENSION",
-			"Sons Of Anarchy", "", "07", "07", "",
+			"Sons Of Anarchy", "", "07", "07", "", nil,
 		},
 		{
 			"[@Difilm] The.Hot.Spot.1990.480p.BluRay.HardSub",
-			"The Hot Spot", "1990", "", "", "",
+			"The Hot Spot", "1990", "", "", "", nil,
 		},
 		{
 			"[@MovieSpecial] Wild.Things.1998.BRRip.HardSub",
-			"Wild Things", "1998", "", "", "",
+			"Wild Things", "1998", "", "", "", nil,
 		},
 		{
 			"[720pMkv.Com]_sons.of.anarchy.s05e10.480p.BluRay.x264-GAnGSteR",
-			"Sons Of Anarchy", "", "05", "10", "",
+			"Sons Of Anarchy", "", "05", "10", "", nil,
 		},
 		{
 			"🃏@film_night🃏Venus in Fur 2013 BluRay 720p",
-			"Venus In Fur", "2013", "", "", "",
+			"Venus In Fur", "2013", "", "", "", nil,
 		},
 		{
 			"2047 - Sights of Death (2014) 720p BrRip x264 - YIFY",
-			"2047 Sights Of Death", "2014", "", "", "",
+			"2047 Sights Of Death", "2014", "", "", "", nil,
 		},
 		{
 			"22 Jump Street (2014) 720p BrRip x264 - YIFY",
-			"22 Jump Street", "2014", "", "", "",
+			"22 Jump Street", "2014", "", "", "", nil,
 		},
 		{
 			"9.Songs.2004.720p.BluRay.HardSub.Digimoviez",
-			"9 Songs", "2004", "", "", "",
+			"9 Songs", "2004", "", "", "", nil,
 		},
 		{
 			"Akira (2016) - UpScaled - 720p - DesiSCR-Rip - Hindi - x264 - AC3 - 5.1 - Mafiaking - M2Tv",
-			"Akira", "2016", "", "", "",
+			"Akira", "2016", "", "", "", nil,
 		},
 		{
 			"American.Gods.S01E01.1080p.HEVC.x265-MeGusta",
-			"American Gods", "", "01", "01", "",
+			"American Gods", "", "01", "01", "", nil,
 		},
 		{
 			"american.gods.s01e02.1080p.webrip.hevc.x265-rmteam",
-			"American Gods", "", "01", "02", "",
+			"American Gods", "", "01", "02", "", nil,
 		},
 		{
 			"Annabelle.2014.1080p.PROPER.HC.WEBRip.x264.AAC.2.0-RARBG",
-			"Annabelle", "2014", "", "", "",
+			"Annabelle", "2014", "", "", "", nil,
 		},
 		{
 			"Annabelle.2014.HC.HDRip.XViD.AC3-juggs[ETRG]",
-			"Annabelle", "2014", "", "", "",
+			"Annabelle", "2014", "", "", "", nil,
 		},
 		{
 			"Ant-Man.2015.3D.1080p.BRRip.Half-SBS.x264.AAC-m2g",
-			"Ant-Man", "2015", "", "", "",
+			"Ant-Man", "2015", "", "", "", nil,
 		},
 		{
 			"Ben Hur 2016 TELESYNC x264 AC3 MAXPRO",
-			"Ben Hur", "2016", "", "", "",
+			"Ben Hur", "2016", "", "", "", nil,
 		},
 		{
 			"Bliss.1997.DVDRip.HardSub",
-			"Bliss", "1997", "", "", "",
+			"Bliss", "1997", "", "", "", nil,
 		},
 		{
 			"Brave.2012.R5.DVDRip.XViD.LiNE-UNiQUE",
-			"Brave", "2012", "", "", "",
+			"Brave", "2012", "", "", "", nil,
 		},
 		{
 			"breaking.bad.s01e01.720p.bluray.x264-reward",
-			"Breaking Bad", "", "01", "01", "",
+			"Breaking Bad", "", "01", "01", "", nil,
 		},
 		{
 			"Caníbal.2013.BluRay.720p.HardSub",
-			"Caníbal", "2013", "", "", "",
+			"Caníbal", "2013", "", "", "", nil,
 		},
 		{
 			"Community.s02e20.rus.eng.720p.Kybik.v.Kybe",
-			"Community", "", "02", "20", "",
+			"Community", "", "02", "20", "", nil,
 		},
 		{
 			"Dawn.Of.The.Planet.of.The.Apes.2014.1080p.WEB-DL.DD51.H264-RARBG",
-			"Dawn Of The Planet Of The Apes", "2014", "", "", "",
+			"Dawn Of The Planet Of The Apes", "2014", "", "", "", nil,
 		},
 		{
 			"Dawn.of.the.Planet.of.the.Apes.2014.HDRip.XViD-EVO",
-			"Dawn Of The Planet Of The Apes", "2014", "", "", "",
+			"Dawn Of The Planet Of The Apes", "2014", "", "", "", nil,
 		},
 		{
 			"Die.Marquise.von.Sade.1976.720p.BluRay.HardSub.Digimoviez",
-			"Die Marquise Von Sade", "1976", "", "", "",
+			"Die Marquise Von Sade", "1976", "", "", "", nil,
 		},
 		{
 			"Dinosaur 13 2014 WEBrip XviD AC3 MiLLENiUM",
-			"Dinosaur 13", "2014", "", "", "",
+			"Dinosaur 13", "2014", "", "", "", nil,
 		},
 		{
 			"Doctor.Who.2005.8x11.Dark.Water.720p.HDTV.x264-FoV[rartv]",
-			"Doctor Who", "2005", "08", "11", "Dark Water",
+			"Doctor Who", "2005", "08", "11", "Dark Water", nil,
 		},
 		// {
 		// 	"doctor_who_2005.8x12.death_in_heaven.720p_hdtv_x264-fov",
-		// 	"Doctor Who", "2005", "08", "12", "Death In Heaven",
+		// 	"Doctor Who", "2005", "08", "12", "Death In Heaven", nil,
 		// },
 		{
 			"Double.Lover.2017.720p.BluRay.HardSub.Digimoviez",
-			"Double Lover", "2017", "", "", "",
+			"Double Lover", "2017", "", "", "", nil,
 		},
 		{
 			"Downton Abbey 5x06 HDTV x264-FoV [eztv]",
-			"Downton Abbey", "", "05", "06", "",
+			"Downton Abbey", "", "05", "06", "", nil,
 		},
 		{
 			"Dracula.Untold.2014.TS.XViD.AC3.MrSeeN-SiMPLE",
-			"Dracula Untold", "2014", "", "", "",
+			"Dracula Untold", "2014", "", "", "", nil,
 		},
 		{
 			"Eliza Graves (2014) Dual Audio WEB-DL 720p MKV x264",
-			"Eliza Graves", "2014", "", "", "",
+			"Eliza Graves", "2014", "", "", "", nil,
 		},
 		{
 			"Femme.Fatale.2002.720p.BluRay.HardSub.mp4",
-			"Femme Fatale", "2002", "", "", "",
+			"Femme Fatale", "2002", "", "", "", nil,
 		},
 		{
 			"Game of Thrones - 4x03 - Breaker of Chains",
-			"Game Of Thrones", "", "04", "03", "Breaker Of Chains",
+			"Game Of Thrones", "", "04", "03", "Breaker Of Chains", nil,
 		},
 		{
 			"Girl House (2015) BluRay 720p-hardsub-(@GalleryMovies)",
-			"Girl House", "2015", "", "", "",
+			"Girl House", "2015", "", "", "", nil,
 		},
 		{
 			"Gotham.S01E05.Viper.WEB-DL.x264.AAC",
-			"Gotham", "", "01", "05", "Viper",
+			"Gotham", "", "01", "05", "Viper", nil,
 		},
 		{
 			"Gotham.S01E07.Penguins.Umbrella.WEB-DL.x264.AAC",
-			"Gotham", "", "01", "07", "Penguins Umbrella",
+			"Gotham", "", "01", "07", "Penguins Umbrella", nil,
 		},
 		{
 			"Guardians of the Galaxy (2014) Dual Audio DVDRip AVI",
-			"Guardians Of The Galaxy", "2014", "", "", "",
+			"Guardians Of The Galaxy", "2014", "", "", "", nil,
 		},
 		{
 			"Guardians Of The Galaxy 2014 R6 720p HDCAM x264-JYK",
-			"Guardians Of The Galaxy", "2014", "", "", "",
+			"Guardians Of The Galaxy", "2014", "", "", "", nil,
 		},
 		{
 			"Guardians of the Galaxy (CamRip - 2014)",
-			"Guardians Of The Galaxy", "2014", "", "", "",
+			"Guardians Of The Galaxy", "2014", "", "", "", nil,
 		},
 		{
 			"Halt.and.Catch.Fire.S04E02.Signal.to.Noise.1080p.AMZN.WEBRip.DDP5.1.x264-NTb[rarbg]",
-			"Halt And Catch Fire", "", "04", "02", "Signal To Noise",
+			"Halt And Catch Fire", "", "04", "02", "Signal To Noise", nil,
 		},
 		{
 			"Halt.and.Catch.Fire.S04E06.CONVERT.1080p.WEB.h264-TBS[rarbg]",
-			"Halt And Catch Fire", "", "04", "06", "",
+			"Halt And Catch Fire", "", "04", "06", "", nil,
 		},
 		{
 			"Halt.and.Catch.Fire.S04E10.1080p.WEB.H264-STRiFE[rarbg]",
-			"Halt And Catch Fire", "", "04", "10", "",
+			"Halt And Catch Fire", "", "04", "10", "", nil,
 		},
 		{
 			"Hercules (2014) 1080p BrRip H264 - YIFY",
-			"Hercules", "2014", "", "", "",
+			"Hercules", "2014", "", "", "", nil,
 		},
 		{
 			"Hercules.2014.EXTENDED.1080p.WEB-DL.DD5.1.H264-RARBG",
-			"Hercules", "2014", "", "", "",
+			"Hercules", "2014", "", "", "", nil,
 		},
 		{
 			"Hercules.2014.Extended.Cut.HDRip.XViD-juggs[ETRG]",
-			"Hercules", "2014", "", "", "",
+			"Hercules", "2014", "", "", "", nil,
 		},
 		{
 			"Hercules (2014) WEBDL DVDRip XviD-MAX",
-			"Hercules", "2014", "", "", "",
+			"Hercules", "2014", "", "", "", nil,
 		},
 		{
 			"Hes.Just.Not.That.Into.You.2009,[@Intermedia]",
-			"Hes Just Not That Into You", "2009", "", "", "",
+			"Hes Just Not That Into You", "2009", "", "", "", nil,
 		},
 		{
 			"Ice.Age.Collision.Course.2016.READNFO.720p.HDRIP.X264.AC3.TiTAN",
-			"Ice Age Collision Course", "2016", "", "", "",
+			"Ice Age Collision Course", "2016", "", "", "", nil,
 		},
 		{
 			"Interstellar (2014) CAM ENG x264 AAC-CPG",
-			"Interstellar", "2014", "", "", "",
+			"Interstellar", "2014", "", "", "", nil,
 		},
 		{
 			"Into The Storm 2014 1080p BRRip x264 DTS-JYK",
-			"Into The Storm", "2014", "", "", "",
+			"Into The Storm", "2014", "", "", "", nil,
 		},
 		{
 			"Into.The.Storm.2014.1080p.WEB-DL.AAC2.0.H264-RARBG",
-			"Into The Storm", "2014", "", "", "",
+			"Into The Storm", "2014", "", "", "", nil,
 		},
 		{
 			"Its.Always.Sunny.In.Philadelphia.S05E02.BDRip",
-			"Its Always Sunny In Philadelphia", "", "05", "02", "",
+			"Its Always Sunny In Philadelphia", "", "05", "02", "", nil,
 		},
 		{
 			"Jack.And.The.Cuckoo-Clock.Heart.2013.BRRip XViD",
-			"Jack And The Cuckoo-Clock Heart", "2013", "", "", "",
+			"Jack And The Cuckoo-Clock Heart", "2013", "", "", "", nil,
 		},
 		{
 			"Last.Tango.in.Paris.1972.720p.BluRay.HardSub",
-			"Last Tango In Paris", "1972", "", "", "",
+			"Last Tango In Paris", "1972", "", "", "", nil,
 		},
 		{
 			"Lets.Be.Cops.2014.BRRip.XViD-juggs[ETRG]",
-			"Lets Be Cops", "2014", "", "", "",
+			"Lets Be Cops", "2014", "", "", "", nil,
 		},
 		{
 			"Lovelace.2013.720p.BluRay-@TheMovieShare",
-			"Lovelace", "2013", "", "", "",
+			"Lovelace", "2013", "", "", "", nil,
 		},
 		{
 			"Lucy 2014 Dual-Audio 720p WEBRip",
-			"Lucy", "2014", "", "", "",
+			"Lucy", "2014", "", "", "", nil,
 		},
 		{
 			"Lucy 2014 Dual-Audio WEBRip 1400Mb",
-			"Lucy", "2014", "", "", "",
+			"Lucy", "2014", "", "", "", nil,
 		},
 		{
 			"Lucy.2014.HC.HDRip.XViD-juggs[ETRG]",
-			"Lucy", "2014", "", "", "",
+			"Lucy", "2014", "", "", "", nil,
 		},
 		{
 			"Malizia.1973.480p.perSub",
-			"Malizia", "1973", "", "", "",
+			"Malizia", "1973", "", "", "", nil,
 		},
 		{
 			"Marvel's.Agents.of.S.H.I.E.L.D.S02E01.Shadows.1080p.WEB-DL.DD5.1",
-			"Marvel'S Agents Of S H I E L D", "", "02", "01", "Shadows",
+			"Marvel'S Agents Of S H I E L D", "", "02", "01", "Shadows", nil,
 		},
 		{
 			"Marvels Agents of S H I E L D S02E05 HDTV x264-KILLERS [eztv]",
-			"Marvels Agents Of S H I E L D", "", "02", "05", "",
+			"Marvels Agents Of S H I E L D", "", "02", "05", "", nil,
 		},
 		{
 			"Marvels Agents of S.H.I.E.L.D. S02E06 HDTV x264-KILLERS[ettv]",
-			"Marvels Agents Of S.H.I.E.L.D.", "", "02", "06", "",
+			"Marvels Agents Of S.H.I.E.L.D.", "", "02", "06", "", nil,
 		},
 		{
 			"Match_Point_2005_hardsub",
-			"Match Point", "2005", "", "", "",
+			"Match Point", "2005", "", "", "", nil,
 		},
 		{
 			"Mektoub.My.Love.Canto.Uno.2017.720p.HardSub",
-			"Mektoub My Love Canto Uno", "2017", "", "", "",
+			"Mektoub My Love Canto Uno", "2017", "", "", "", nil,
 		},
 		{
 			"One Shot [2014] DVDRip XViD-ViCKY",
-			"One Shot", "2014", "", "", "",
+			"One Shot", "2014", "", "", "", nil,
 		},
 		{
 			"Red.Sonja.Queen.Of.Plagues.2016.BDRip.x264-W4F[PRiME]",
-			"Red Sonja Queen Of Plagues", "2016", "", "", "",
+			"Red Sonja Queen Of Plagues", "2016", "", "", "", nil,
 		},
 		{
 			"Return.To.Snowy.River.1988.iNTERNAL.DVDRip.x264-W4F[PRiME]",
-			"Return To Snowy River", "1988", "", "", "",
+			"Return To Snowy River", "1988", "", "", "", nil,
 		},
 		{
 			"rick.and.morty.s03e01.720p.hdtv.x264-w4f",
-			"Rick And Morty", "", "03", "01", "",
+			"Rick And Morty", "", "03", "01", "", nil,
 		},
 		{
 			"Silicon.Valley.S04E04.1080p.WEB.h264-TBS",
-			"Silicon Valley", "", "04", "04", "",
+			"Silicon Valley", "", "04", "04", "", nil,
 		},
 		{
 			"Sin.City.A.Dame.to.Kill.For.2014.1080p.BluRay.x264-SPARKS",
-			"Sin City A Dame To Kill For", "2014", "", "", "",
+			"Sin City A Dame To Kill For", "2014", "", "", "", nil,
 		},
 		{
 			"Sister.Emanuelle.DvdRip.HardSub",
-			"Sister Emanuelle", "", "", "", "",
+			"Sister Emanuelle", "", "", "", "", nil,
 		},
 		{
 			"Sons.of.Anarchy.S01E03",
-			"Sons Of Anarchy", "", "01", "03", "",
+			"Sons Of Anarchy", "", "01", "03", "", nil,
 		},
 		{
 			"South Park S18E05 HDTV x264-KILLERS [eztv]",
-			"South Park", "", "18", "05", "",
+			"South Park", "", "18", "05", "", nil,
 		},
 		{
 			"Teenage.Mutant.Ninja.Turtles.2014.720p.HDRip.x264.AC3.5.1-RARBG",
-			"Teenage Mutant Ninja Turtles", "2014", "", "", "",
+			"Teenage Mutant Ninja Turtles", "2014", "", "", "", nil,
 		},
 		{
 			"Teenage.Mutant.Ninja.Turtles.2014.HDRip.XviD.MP3-RARBG",
-			"Teenage Mutant Ninja Turtles", "2014", "", "", "",
+			"Teenage Mutant Ninja Turtles", "2014", "", "", "", nil,
 		},
 		{
 			"Teenage Mutant Ninja Turtles (HdRip - 2014)",
-			"Teenage Mutant Ninja Turtles", "2014", "", "", "",
+			"Teenage Mutant Ninja Turtles", "2014", "", "", "", nil,
 		},
 		{
 			"Teenage Mutant Ninja Turtles (unknown_release_type - 2014)",
-			"Teenage Mutant Ninja Turtles", "2014", "", "", "",
+			"Teenage Mutant Ninja Turtles", "2014", "", "", "", nil,
 		},
 		{
 			"Teeth_2007",
-			"Teeth", "2007", "", "", "",
+			"Teeth", "2007", "", "", "", nil,
 		},
 		{
 			"The Big Bang Theory S08E06 HDTV XviD-LOL [eztv]",
-			"The Big Bang Theory", "", "08", "06", "",
+			"The Big Bang Theory", "", "08", "06", "", nil,
 		},
 		{
 			"The.Boss.2016.UNRATED.720p.BRRip.x264.AAC-ETRG",
-			"The Boss", "2016", "", "", "",
+			"The Boss", "2016", "", "", "", nil,
 		},
 		{
 			"The.Dark.Side.of.the.Heart.DVDRip.HardSub",
-			"The Dark Side Of The Heart", "", "", "", "",
+			"The Dark Side Of The Heart", "", "", "", "", nil,
 		},
 		{
 			"The.Duke.of.Burgundy.2014.720p.BluRay.HardSub",
-			"The Duke Of Burgundy", "2014", "", "", "",
+			"The Duke Of Burgundy", "2014", "", "", "", nil,
 		},
 		{
 			"The Flash 2014 S01E01 HDTV x264-LOL[ettv]",
-			"The Flash", "2014", "01", "01", "",
+			"The Flash", "2014", "01", "01", "", nil,
 		},
 		{
 			"The Flash 2014 S01E03 HDTV x264-LOL[ettv]",
-			"The Flash", "2014", "01", "03", "",
+			"The Flash", "2014", "01", "03", "", nil,
 		},
 		{
 			"The Flash 2014 S01E04 HDTV x264-FUM[ettv]",
-			"The Flash", "2014", "01", "04", "",
+			"The Flash", "2014", "01", "04", "", nil,
 		},
 		{
 			"The Hateful Eight (2015) 720p BluRay - x265 HEVC - 999MB - ShAaN",
-			"The Hateful Eight", "2015", "", "", "",
+			"The Hateful Eight", "2015", "", "", "", nil,
 		},
 		{
 			"The.Jungle.Book.2016.3D.1080p.BRRip.SBS.x264.AAC-ETRG",
-			"The Jungle Book", "2016", "", "", "",
+			"The Jungle Book", "2016", "", "", "", nil,
 		},
 		{
 			"The Missing 1x01 Pilot HDTV x264-FoV [eztv]",
-			"The Missing", "", "01", "01", "Pilot",
+			"The Missing", "", "01", "01", "Pilot", nil,
 		},
 		{
 			"The.Platform.2019.720p.WEB-DL.SoftSub",
-			"The Platform", "2019", "", "", "",
+			"The Platform", "2019", "", "", "", nil,
 		},
 		{
 			"The Purge: Election Year (2016) HC - 720p HDRiP - 900MB - ShAaNi",
-			"The Purge: Election Year", "2016", "", "", "",
+			"The Purge: Election Year", "2016", "", "", "", nil,
 		},
 		{
 			"The.Secret.Life.of.Pets.2016.HDRiP.AAC-LC.x264-LEGi0N",
-			"The Secret Life Of Pets", "2016", "", "", "",
+			"The Secret Life Of Pets", "2016", "", "", "", nil,
 		},
 		{
 			"These.Final.Hours.2013.WBBRip XViD",
-			"These Final Hours", "2013", "", "", "",
+			"These Final Hours", "2013", "", "", "", nil,
 		},
 		{
 			"The Shaukeens (2014) 1CD DvDScr Rip x264 [DDR]",
-			"The Shaukeens", "2014", "", "", "",
+			"The Shaukeens", "2014", "", "", "", nil,
 		},
 		{
 			"The Shaukeens 2014 Hindi (1CD) DvDScr x264 AAC...Hon3y",
-			"The Shaukeens", "2014", "", "", "",
+			"The Shaukeens", "2014", "", "", "", nil,
 		},
 		{
 			"The Simpsons S26E05 HDTV x264 PROPER-LOL [eztv]",
-			"The Simpsons", "", "26", "05", "",
+			"The Simpsons", "", "26", "05", "", nil,
 		},
 		{
 			"The.Walking.Dead.S05E03.1080p.WEB-DL.DD5.1.H.264-Cyphanix[rartv]",
-			"The Walking Dead", "", "05", "03", "",
+			"The Walking Dead", "", "05", "03", "", nil,
 		},
 		{
 			"The Walking Dead S05E03 720p HDTV x264-ASAP[ettv]",
-			"The Walking Dead", "", "05", "03", "",
+			"The Walking Dead", "", "05", "03", "", nil,
 		},
 		{
 			"The.Wings.of.The.Dove.1997.720p.HardSub",
-			"The Wings Of The Dove", "1997", "", "", "",
+			"The Wings Of The Dove", "1997", "", "", "", nil,
 		},
 		{
 			"They.2017.WEBRip.1080p.YTS.Dream",
-			"They", "2017", "", "", "",
+			"They", "2017", "", "", "", nil,
 		},
 		{
 			"Trainwreck",
-			"Trainwreck", "", "", "", "",
+			"Trainwreck", "", "", "", "", nil,
 		},
 		{
 			"Two and a Half Men S12E01 HDTV x264 REPACK-LOL [eztv]",
-			"Two And A Half Men", "", "12", "01", "",
+			"Two And A Half Men", "", "12", "01", "", nil,
 		},
 		{
 			"UFC.179.PPV.HDTV.x264-Ebi[rartv]",
-			"UFC 179", "", "", "", "",
+			"UFC 179", "", "", "", "", nil,
 		},
 		{
 			"War Dogs (2016) HDTS 600MB - NBY",
-			"War Dogs", "2016", "", "", "",
+			"War Dogs", "2016", "", "", "", nil,
 		},
 		{
 			"Wild.Things.2.2004.720p.HardSub",
-			"Wild Things 2", "2004", "", "", "",
+			"Wild Things 2", "2004", "", "", "", nil,
 		},
 		{
 			"WWE Hell in a Cell 2014 HDTV x264 SNHD",
-			"WWE Hell In A Cell", "2014", "", "", "",
+			"WWE Hell In A Cell", "2014", "", "", "", nil,
 		},
 		{
 			"WWE Hell in a Cell 2014 PPV WEB-DL x264-WD -={SPARROW}=-",
-			"WWE Hell In A Cell", "2014", "", "", "",
+			"WWE Hell In A Cell", "2014", "", "", "", nil,
 		},
 		{
 			"WWE Monday Night Raw 2014 11 10 WS PDTV x264-RKOFAN1990 -={SPARR",
-			"WWE Monday Night Raw 11 10", "2014", "", "", "",
+			"WWE Monday Night Raw", "", "2014", "", "", nil,
 		},
 		{
 			"WWE Monday Night Raw 3rd Nov 2014 HDTV x264-Sir Paul",
-			"WWE Monday Night Raw 3rd Nov", "2014", "", "", "",
+			"WWE Monday Night Raw 3rd Nov", "2014", "", "", "", nil,
 		},
 		{
 			"www.torrenting.com - Silicon.Valley.S04E04.1080p.WEB.h264-TBS",
-			"Silicon Valley", "", "04", "04", "",
+			"Silicon Valley", "", "04", "04", "", nil,
 		},
 		{
 			"X-Men.Days.of.Future.Past.2014.1080p.WEB-DL.DD5.1.H264-RARBG",
-			"X-Men Days Of Future Past", "2014", "", "", "",
+			"X-Men Days Of Future Past", "2014", "", "", "", nil,
 		},
 		{
 			"filmpokvipNo_Hard_Feelings_2023_1080p_WEBRip_x265_10bit_AAC5_1_YT",
-			"No Hard Feelings", "2023", "", "", "",
+			"No Hard Feelings", "2023", "", "", "", nil,
 		},
 		{
 			"Venus in Fur.2013",
-			"Venus In Fur", "2013", "", "", "",
+			"Venus In Fur", "2013", "", "", "", nil,
 		},
 		{
 			"Mission_Impossible_Dead_Reckoning_Part_One_2023_1080P_Amzn_Web",
-			"Mission Impossible Dead Reckoning Part One", "2023", "", "", "",
+			"Mission Impossible Dead Reckoning Part One", "2023", "", "", "", nil,
 		},
 		{
 			"The_Swan_2023_10bit_1080p_x265_WEB-DL",
-			"The Swan", "2023", "", "", "",
+			"The Swan", "2023", "", "", "", nil,
 		},
 		{
 			"Anatomy.Of.A.Fall.2023.FRENCH.ENSUB.720p.WEBRip.x264",
-			"Anatomy Of A Fall", "2023", "", "", "",
+			"Anatomy Of A Fall", "2023", "", "", "", nil,
 		},
 		{
 			"Film_pok.Drive.2011.720p.BluRay.x264.AAC",
-			"Drive", "2011", "", "", "",
+			"Drive", "2011", "", "", "", nil,
 		},
 		{
 			"Ordinary.Angels.2024.1080P.Webrip.X264.Aac5.1-[Yts.Mx]",
-			"Ordinary Angels", "2024", "", "", "",
+			"Ordinary Angels", "2024", "", "", "", nil,
 		},
 		{
 			"Maxxxine.2024.720P.Web.H264-Sitbackandrelaxxx",
-			"Maxxxine", "2024", "", "", "",
+			"Maxxxine", "2024", "", "", "", nil,
 		},
 		{
 			"Twisters.2024.720P.Web-Dl.Ddp5.1.Atmos.H.264.Flux",
-			"Twisters", "2024", "", "", "",
+			"Twisters", "2024", "", "", "", nil,
 		},
 		{
 			"Borderlands.2024.720P.Web-Dl.H264",
-			"Borderlands", "2024", "", "", "",
+			"Borderlands", "2024", "", "", "", nil,
 		},
 		{
 			"The.Damned.2024.Dvd.576P.Remux",
-			"The Damned", "2024", "", "", "",
+			"The Damned", "2024", "", "", "", nil,
 		},
 		// {
 		// 	"The.Good.Teacher.2024_720p_WEB-DL_AAC_YIFY",
-		// 	"The Good Teacher", "2024", "", "", "",
+		// 	"The Good Teacher", "2024", "", "", "", nil,
 		// },
 		{
 			"Deadpool_And_Wolverine_2024_720P_Web_Dl_Ddp5_1_Atmos_H_264_Flux",
-			"Deadpool And Wolverine", "2024", "", "", "",
+			"Deadpool And Wolverine", "2024", "", "", "", nil,
 		},
 		{
 			"Alien.Romulus.2024.720P.Web-Dl.H264.Ethel",
-			"Alien Romulus", "2024", "", "", "",
+			"Alien Romulus", "2024", "", "", "", nil,
+		},
+		{
+			"Show.S02E05E06.720p.mkv",
+			"Show", "", "02", "05", "", []string{"05", "06"},
+		},
+		{
+			"Show.S01E01-E03.Pilot.720p.HDTV",
+			"Show", "", "01", "01", "Pilot", []string{"01", "02", "03"},
+		},
+		{
+			"Show 1x01-1x02 720p",
+			"Show", "", "01", "01", "", []string{"01", "02"},
+		},
+		{
+			"Show.2010.S03E09-10.1080p.WEB-DL",
+			"Show", "2010", "03", "09", "", []string{"09", "10"},
+		},
+		{
+			"Show.S01E01-720p.HDTV",
+			"Show", "", "01", "01", "", nil,
+		},
+		{
+			"The.DV.Files.2019.1080p.WEBRip",
+			"The DV Files", "2019", "", "", "", nil,
+		},
+		{
+			"Movie.2019.UHD.Atmos.BluRay",
+			"Movie", "2019", "", "", "", nil,
+		},
+		{
+			"THE.ENGLISH.PATIENT.1996.720p.BLURAY.X264",
+			"THE ENGLISH PATIENT", "1996", "", "", "", nil,
+		},
+		{
+			"THE.ITALIAN.JOB.2003.1080p.BLURAY",
+			"THE ITALIAN JOB", "2003", "", "", "", nil,
+		},
+	}
+
+	for _, tt := range ts {
+		m := parse(tt.n)
+		if m.Title != tt.m {
+			t.Errorf("name: %s\ngot:  %s\nwant: %s", tt.n, m.Title, tt.m)
+		}
+		if m.Year != tt.y {
+			t.Errorf("year: %s\ngot:  %s\nwant: %s", tt.n, m.Year, tt.y)
+		}
+		if m.Season != tt.s {
+			t.Errorf("season: %s\ngot:  %s\nwant: %s", tt.n, m.Season, tt.s)
+		}
+		if m.Episode != tt.e {
+			t.Errorf("episode: %s\ngot:  %s\nwant: %s", tt.n, m.Episode, tt.e)
+		}
+		if !reflect.DeepEqual(m.Episodes, tt.es) {
+			t.Errorf("episodes: %s\ngot:  %v\nwant: %v", tt.n, m.Episodes, tt.es)
+		}
+		if m.EpisodeTitle != tt.en {
+			t.Errorf("episode name: %s\ngot:  %s\nwant: %s", tt.n, m.EpisodeTitle, tt.en)
+		}
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	ts := []struct {
+		f   string
+		m   Media
+		err error
+	}{
+		{
+			"foo.2020.MKV",
+			Media{Title: "Foo", Year: "2020", Ext: ".mkv"},
+			nil,
+		},
+		{
+			filepath.Join("dir", "foo.s01e02.bar.mkv"),
+			Media{Title: "Foo", Season: "01", Episode: "02", EpisodeTitle: "Bar", Ext: ".mkv"},
+			nil,
+		},
+		{
+			"",
+			Media{},
+			ErrNoTitle,
+		},
+	}
+
+	for _, tt := range ts {
+		m, err := Parse(tt.f)
+		if err != tt.err {
+			t.Errorf("error: %s\ngot:  %v\nwant: %v", tt.f, err, tt.err)
+		}
+		if !reflect.DeepEqual(m, tt.m) {
+			t.Errorf("media: %s\ngot:  %+v\nwant: %+v", tt.f, m, tt.m)
+		}
+	}
+}
+
+func TestParser(t *testing.T) {
+	p := NewParser([]string{"SiteName", "-SiteTag"})
+
+	ts := []struct {
+		n    string
+		p, d string
+	}{
+		{"SiteName.Movie.2019.1080p.mkv", "Movie", "SiteName Movie"},
+		{"Show.S01E02-SiteTag.mkv", "Show", "Show"},
+		{"filmpokvip.Movie.2019.mkv", "Movie", "Movie"},
+	}
+
+	for _, tt := range ts {
+		if m, err := p.Parse(tt.n); err != nil || m.Title != tt.p {
+			t.Errorf("name: %s\ngot:  %s %v\nwant: %s", tt.n, m.Title, err, tt.p)
+		}
+		if m, err := Parse(tt.n); err != nil || m.Title != tt.d {
+			t.Errorf("default name: %s\ngot:  %s %v\nwant: %s", tt.n, m.Title, err, tt.d)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	ts := []struct {
+		n           string
+		m, d, s, en string
+		pn          string
+	}{
+		{
+			"The.Daily.Show.2020.10.15.Guest.Name.720p.WEB",
+			"The Daily Show", "2020-10-15", "2020", "Guest Name",
+			"The Daily Show - 2020-10-15 - Guest Name",
+		},
+		{
+			"The Tonight Show 2019-03-07 720p HDTV",
+			"The Tonight Show", "2019-03-07", "2019", "",
+			"The Tonight Show - 2019-03-07",
+		},
+		{
+			"Evening.News.07.03.2019.HDTV.x264",
+			"Evening News", "2019-03-07", "2019", "",
+			"Evening News - 2019-03-07",
+		},
+		{
+			"Movie.2019.720p.BluRay",
+			"Movie", "", "", "",
+			"Movie (2019)",
+		},
+		{
+			"Oceans.11.12.2001.720p",
+			"Oceans 11 12", "", "", "",
+			"Oceans 11 12 (2001)",
+		},
+		{
+			"Evening News - 17.11.2019 HDTV",
+			"Evening News", "2019-11-17", "2019", "",
+			"Evening News - 2019-11-17",
+		},
+		{
+			"Show.2019.02.30.720p",
+			"Show 02 30", "", "", "",
+			"Show 02 30 (2019)",
+		},
+		{
+			"Show.S02E05.2020.10.15.720p",
+			"Show", "", "02", "",
+			"Show - s02e05",
+		},
+		{
+			"Show.2020.10.15.S02E05.720p",
+			"Show", "", "02", "",
+			"Show - s02e05",
+		},
+	}
+
+	for _, tt := range ts {
+		m := parse(tt.n)
+		if m.Title != tt.m {
+			t.Errorf("name: %s\ngot:  %s\nwant: %s", tt.n, m.Title, tt.m)
+		}
+		if m.Date != tt.d {
+			t.Errorf("date: %s\ngot:  %s\nwant: %s", tt.n, m.Date, tt.d)
+		}
+		if m.Season != tt.s {
+			t.Errorf("season: %s\ngot:  %s\nwant: %s", tt.n, m.Season, tt.s)
+		}
+		if m.EpisodeTitle != tt.en {
+			t.Errorf("episode name: %s\ngot:  %s\nwant: %s", tt.n, m.EpisodeTitle, tt.en)
+		}
+		if pn := m.Name(); pn != tt.pn {
+			t.Errorf("plex name: %s\ngot:  %s\nwant: %s", tt.n, pn, tt.pn)
+		}
+	}
+}
+
+func TestParseAnime(t *testing.T) {
+	ts := []struct {
+		n                   string
+		m, s, e, en, rv, cr string
+		pn                  string
+	}{
+		{
+			"[SubsPlease] Jujutsu Kaisen - 05 [1080p][ABCD1234]",
+			"Jujutsu Kaisen", "", "05", "", "", "ABCD1234",
+			"Jujutsu Kaisen - e05",
+		},
+		{
+			"[HorribleSubs]_Naruto_Shippuuden_-_123v2_[720p]",
+			"Naruto Shippuuden", "", "123", "", "2", "",
+			"Naruto Shippuuden - e123",
+		},
+		{
+			"[Erai-raws] Kimetsu no Yaiba - 1 - Cruelty [1080p][Multiple Subtitle][9f3c2b1a]",
+			"Kimetsu No Yaiba", "", "01", "Cruelty", "", "9F3C2B1A",
+			"Kimetsu No Yaiba - e01 - Cruelty",
+		},
+		{
+			"[SubsPlease] Jujutsu Kaisen S2 - 03 (1080p) [E1A2B3C4]",
+			"Jujutsu Kaisen", "02", "03", "", "", "E1A2B3C4",
+			"Jujutsu Kaisen - s02e03",
+		},
+		{
+			"[Group] One Piece - 1071v3 [BD 1080p]",
+			"One Piece", "", "1071", "", "3", "",
+			"One Piece - e1071",
+		},
+	}
+
+	for _, tt := range ts {
+		m := parse(tt.n)
+		if m.Title != tt.m {
+			t.Errorf("name: %s\ngot:  %s\nwant: %s", tt.n, m.Title, tt.m)
+		}
+		if m.Season != tt.s {
+			t.Errorf("season: %s\ngot:  %s\nwant: %s", tt.n, m.Season, tt.s)
+		}
+		if m.Episode != tt.e {
+			t.Errorf("episode: %s\ngot:  %s\nwant: %s", tt.n, m.Episode, tt.e)
+		}
+		if m.EpisodeTitle != tt.en {
+			t.Errorf("episode name: %s\ngot:  %s\nwant: %s", tt.n, m.EpisodeTitle, tt.en)
+		}
+		if m.Revision != tt.rv {
+			t.Errorf("revision: %s\ngot:  %s\nwant: %s", tt.n, m.Revision, tt.rv)
+		}
+		if m.CRC != tt.cr {
+			t.Errorf("crc: %s\ngot:  %s\nwant: %s", tt.n, m.CRC, tt.cr)
+		}
+		if pn := m.Name(); pn != tt.pn {
+			t.Errorf("plex name: %s\ngot:  %s\nwant: %s", tt.n, pn, tt.pn)
+		}
+	}
+}
+
+func TestParseIn(t *testing.T) {
+	ts := []struct {
+		d, f       string
+		m, y, s, e string
+		en         string
+		err        error
+	}{
+		{
+			"Breaking.Bad.S03.1080p.BluRay", "S03E01.mkv",
+			"Breaking Bad", "", "03", "01", "", nil,
+		},
+		{
+			"Breaking.Bad.S03.1080p.BluRay", "bb.s03e02.720p.mkv",
+			"Breaking Bad", "", "03", "02", "", nil,
+		},
+		{
+			"Breaking.Bad.S03.1080p.BluRay", "03 - Sunset.mkv",
+			"Breaking Bad", "", "03", "03", "Sunset", nil,
+		},
+		{
+			"Breaking Bad (2008) Season 3", "Episode 4.mkv",
+			"Breaking Bad", "2008", "03", "04", "", nil,
+		},
+		{
+			"Breaking.Bad.S03.1080p.BluRay", "Better.Call.Saul.S01E01.mkv",
+			"Better Call Saul", "", "01", "01", "", nil,
+		},
+		{
+			"Movies", "The.Platform.2019.720p.mkv",
+			"The Platform", "2019", "", "", "", nil,
+		},
+		{
+			"Movies", "03 - Sunset.mkv",
+			"03 Sunset", "", "", "", "", nil,
+		},
+	}
+
+	for _, tt := range ts {
+		d, err := ParseDir(tt.d)
+		if err != nil {
+			t.Errorf("dir: %s\ngot:  %v\nwant: %v", tt.d, err, nil)
+		}
+		m, err := ParseIn(tt.f, d)
+		if err != tt.err {
+			t.Errorf("error: %s\ngot:  %v\nwant: %v", tt.f, err, tt.err)
+		}
+		if m.Title != tt.m {
+			t.Errorf("name: %s\ngot:  %s\nwant: %s", tt.f, m.Title, tt.m)
+		}
+		if m.Year != tt.y {
+			t.Errorf("year: %s\ngot:  %s\nwant: %s", tt.f, m.Year, tt.y)
+		}
+		if m.Season != tt.s {
+			t.Errorf("season: %s\ngot:  %s\nwant: %s", tt.f, m.Season, tt.s)
+		}
+		if m.Episode != tt.e {
+			t.Errorf("episode: %s\ngot:  %s\nwant: %s", tt.f, m.Episode, tt.e)
+		}
+		if m.EpisodeTitle != tt.en {
+			t.Errorf("episode name: %s\ngot:  %s\nwant: %s", tt.f, m.EpisodeTitle, tt.en)
+		}
+	}
+}
+
+func TestParsePart(t *testing.T) {
+	ts := []struct {
+		n        string
+		m, y, pt string
+		pn       string
+	}{
+		{
+			"Movie.2003.CD1",
+			"Movie", "2003", "cd1",
+			"Movie (2003) - cd1",
+		},
+		{
+			"Movie.2003.DVDRip.XviD.CD2",
+			"Movie", "2003", "cd2",
+			"Movie (2003) - cd2",
+		},
+		{
+			"Movie 2003 Part 2 720p",
+			"Movie", "2003", "part2",
+			"Movie (2003) - part2",
+		},
+		{
+			"Movie.pt1",
+			"Movie", "", "pt1",
+			"Movie - pt1",
+		},
+		{
+			"Movie.2003.Disc.02",
+			"Movie", "2003", "disc2",
+			"Movie (2003) - disc2",
+		},
+		{
+			"Harry.Potter.and.the.Deathly.Hallows.Part.1.2010.720p",
+			"Harry Potter And The Deathly Hallows Part 1", "2010", "",
+			"Harry Potter And The Deathly Hallows Part 1 (2010)",
+		},
+	}
+
+	for _, tt := range ts {
+		m := parse(tt.n)
+		if m.Title != tt.m {
+			t.Errorf("name: %s\ngot:  %s\nwant: %s", tt.n, m.Title, tt.m)
+		}
+		if m.Year != tt.y {
+			t.Errorf("year: %s\ngot:  %s\nwant: %s", tt.n, m.Year, tt.y)
+		}
+		if m.Part != tt.pt {
+			t.Errorf("part: %s\ngot:  %s\nwant: %s", tt.n, m.Part, tt.pt)
+		}
+		if pn := m.Name(); pn != tt.pn {
+			t.Errorf("plex name: %s\ngot:  %s\nwant: %s", tt.n, pn, tt.pn)
+		}
+	}
+}
+
+func TestParseEdition(t *testing.T) {
+	ts := []struct {
+		n        string
+		m, y, ed string
+		pn, pd   string
+	}{
+		{
+			"Hercules.2014.EXTENDED.1080p.WEB-DL.DD5.1.H264-RARBG",
+			"Hercules", "2014", "Extended",
+			"Hercules (2014) {edition-Extended}", "Hercules (2014) {edition-Extended}",
+		},
+		{
+			"Hercules.2014.Extended.Cut.HDRip.XViD-juggs[ETRG]",
+			"Hercules", "2014", "Extended",
+			"Hercules (2014) {edition-Extended}", "Hercules (2014) {edition-Extended}",
+		},
+		{
+			"Blade.Runner.1982.Final.Cut.1080p.BluRay",
+			"Blade Runner", "1982", "Final Cut",
+			"Blade Runner (1982) {edition-Final Cut}", "Blade Runner (1982) {edition-Final Cut}",
+		},
+		{
+			"Kingdom.of.Heaven.2005.Directors.Cut.720p.BluRay.x264",
+			"Kingdom Of Heaven", "2005", "Director's Cut",
+			"Kingdom Of Heaven (2005) {edition-Director's Cut}", "Kingdom Of Heaven (2005) {edition-Director's Cut}",
+		},
+		{
+			"Aliens.1986.REMASTERED.1080p.BluRay.IMAX.CD1",
+			"Aliens", "1986", "Remastered IMAX",
+			"Aliens (1986) {edition-Remastered IMAX} - cd1", "Aliens (1986) {edition-Remastered IMAX}",
+		},
+		{
+			"The.Final.Cut.2004.720p.BluRay",
+			"The Final Cut", "2004", "",
+			"The Final Cut (2004)", "The Final Cut (2004)",
+		},
+		{
+			"Show.S01E01.Extended.720p.HDTV",
+			"Show", "", "Extended",
+			"Show - s01e01", "Show",
+		},
+	}
+
+	for _, tt := range ts {
+		m := parse(tt.n)
+		if m.Title != tt.m {
+			t.Errorf("name: %s\ngot:  %s\nwant: %s", tt.n, m.Title, tt.m)
+		}
+		if m.Year != tt.y {
+			t.Errorf("year: %s\ngot:  %s\nwant: %s", tt.n, m.Year, tt.y)
+		}
+		if m.Edition != tt.ed {
+			t.Errorf("edition: %s\ngot:  %s\nwant: %s", tt.n, m.Edition, tt.ed)
+		}
+		if pn := m.Name(); pn != tt.pn {
+			t.Errorf("plex name: %s\ngot:  %s\nwant: %s", tt.n, pn, tt.pn)
+		}
+		if pd := m.Dir(); pd != tt.pd {
+			t.Errorf("plex dir: %s\ngot:  %s\nwant: %s", tt.n, pd, tt.pd)
+		}
+	}
+}
+
+func TestParseID(t *testing.T) {
+	ts := []struct {
+		n    string
+		m, y string
+		ids  IDs
+		pd   string
+	}{
+		{
+			"Batman.Begins.2005.{imdb-tt0372784}.1080p.BluRay",
+			"Batman Begins", "2005", IDs{IMDb: "tt0372784"},
+			"Batman Begins (2005) {imdb-tt0372784}",
+		},
+		{
+			"Batman Begins (2005) tt0372784 720p",
+			"Batman Begins", "2005", IDs{IMDb: "tt0372784"},
+			"Batman Begins (2005) {imdb-tt0372784}",
+		},
+		{
+			"Batman.Begins.2005.[tmdbid-272].1080p",
+			"Batman Begins", "2005", IDs{TMDb: "272"},
+			"Batman Begins (2005) {tmdb-272}",
+		},
+		{
+			"Breaking.Bad.{tvdb-81189}.{imdb-tt0903747}.S01E01.720p",
+			"Breaking Bad", "", IDs{IMDb: "tt0903747", TVDb: "81189"},
+			"Breaking Bad {tvdb-81189}",
 		},
 	}
 
 	for _, tt := range ts {
-		pf := &plexFile{name: tt.n, mov: movie{}}
-		pf.parse()
-		if pf.mov.name != tt.m {
-			t.Errorf("name: %s\ngot:  %s\nwant: %s", tt.n, pf.mov.name, tt.m)
+		m := parse(tt.n)
+		if m.Title != tt.m {
+			t.Errorf("name: %s\ngot:  %s\nwant: %s", tt.n, m.Title, tt.m)
 		}
-		if pf.mov.year != tt.y {
-			t.Errorf("year: %s\ngot:  %s\nwant: %s", tt.n, pf.mov.year, tt.y)
+		if m.Year != tt.y {
+			t.Errorf("year: %s\ngot:  %s\nwant: %s", tt.n, m.Year, tt.y)
 		}
-		if pf.mov.season != tt.s {
-			t.Errorf("season: %s\ngot:  %s\nwant: %s", tt.n, pf.mov.season, tt.s)
+		if m.IDs != tt.ids {
+			t.Errorf("ids: %s\ngot:  %+v\nwant: %+v", tt.n, m.IDs, tt.ids)
 		}
-		if pf.mov.episode != tt.e {
-			t.Errorf("episode: %s\ngot:  %s\nwant: %s", tt.n, pf.mov.episode, tt.e)
+		if pd := m.Dir(); pd != tt.pd {
+			t.Errorf("plex dir: %s\ngot:  %s\nwant: %s", tt.n, pd, tt.pd)
 		}
-		if pf.mov.epiName != tt.en {
-			t.Errorf("episode name: %s\ngot:  %s\nwant: %s", tt.n, pf.mov.epiName, tt.en)
+	}
+}
+
+func TestSetID(t *testing.T) {
+	ts := []struct {
+		id  string
+		ids IDs
+		err bool
+	}{
+		{"tt0372784", IDs{IMDb: "tt0372784"}, false},
+		{"imdb-tt0372784", IDs{IMDb: "tt0372784"}, false},
+		{"{tmdb-272}", IDs{TMDb: "272"}, false},
+		{"tvdb-81189", IDs{TVDb: "81189"}, false},
+		{"272", IDs{}, true},
+		{"tmdb-272 foo", IDs{}, true},
+	}
+
+	for _, tt := range ts {
+		var m Media
+		err := m.SetID(tt.id)
+		if (err != nil) != tt.err {
+			t.Errorf("error: %s\ngot:  %v\nwant: %v", tt.id, err, tt.err)
+		}
+		if m.IDs != tt.ids {
+			t.Errorf("ids: %s\ngot:  %+v\nwant: %+v", tt.id, m.IDs, tt.ids)
 		}
 	}
 }
 
-func TestPlexName(t *testing.T) {
+func TestName(t *testing.T) {
 	ts := []struct {
 		m, y, s, e, en string
+		es             []string
 		n              string
 	}{
 		{
-			"", "", "", "", "",
+			"", "", "", "", "", nil,
 			"",
 		},
 		{
-			"foo", "", "", "", "",
+			"foo", "", "", "", "", nil,
 			"foo",
 		},
 		{
-			"foo", "1986", "", "", "",
+			"foo", "1986", "", "", "", nil,
 			"foo (1986)",
 		},
 		{
-			"bar", "", "03", "07", "",
+			"bar", "", "03", "07", "", nil,
 			"bar - s03e07",
 		},
 		{
-			"bar", "2014", "03", "07", "",
+			"bar", "2014", "03", "07", "", nil,
 			"bar (2014) - s03e07",
 		},
 		{
-			"baz", "", "11", "22", "blah",
+			"baz", "", "11", "22", "blah", nil,
 			"baz - s11e22 - blah",
 		},
 		{
-			"baz", "2020", "11", "22", "blah",
+			"baz", "2020", "11", "22", "blah", nil,
 			"baz (2020) - s11e22 - blah",
 		},
+		{
+			"qux", "", "02", "05", "", []string{"05", "06"},
+			"qux - s02e05-e06",
+		},
+		{
+			"qux", "2012", "01", "01", "pilot", []string{"01", "02", "03"},
+			"qux (2012) - s01e01-e03 - pilot",
+		},
 	}
 
 	for _, tt := range ts {
-		pf := &plexFile{mov: movie{name: tt.m, year: tt.y, season: tt.s, episode: tt.e, epiName: tt.en}}
-		pn := pf.plexName()
+		m := Media{Title: tt.m, Year: tt.y, Season: tt.s, Episode: tt.e, Episodes: tt.es, EpisodeTitle: tt.en}
+		pn := m.Name()
 		if pn != tt.n {
 			t.Errorf("got:  %s\nwant: %s", pn, tt.n)
 		}
 	}
 }
 
-func TestPlexDir(t *testing.T) {
+func TestDir(t *testing.T) {
 	ts := []struct {
 		m, y, s, e, en string
 		d              string
@@ -610,8 +1100,8 @@ func TestPlexDir(t *testing.T) {
 	}
 
 	for _, tt := range ts {
-		pf := &plexFile{mov: movie{name: tt.m, year: tt.y, season: tt.s, episode: tt.e, epiName: tt.en}}
-		pd := pf.plexDir()
+		m := Media{Title: tt.m, Year: tt.y, Season: tt.s, Episode: tt.e, EpisodeTitle: tt.en}
+		pd := m.Dir()
 		if pd != tt.d {
 			t.Errorf("got:  %s\nwant: %s", pd, tt.d)
 		}
@@ -654,117 +1144,16 @@ func TestSeasonDir(t *testing.T) {
 	}
 
 	for _, tt := range ts {
-		pf := &plexFile{mov: movie{name: tt.m, year: tt.y, season: tt.s, episode: tt.e, epiName: tt.en}}
-		pd := pf.seasonDir()
+		m := Media{Title: tt.m, Year: tt.y, Season: tt.s, Episode: tt.e, EpisodeTitle: tt.en}
+		pd := m.SeasonDir()
 		if pd != tt.d {
 			t.Errorf("got:  %s\nwant: %s", pd, tt.d)
 		}
 	}
 }
 
-func TestConvert(t *testing.T) {
-	const mn = "foo.2020.abc"
-	const tn = "foo.s01e02.bar.abc"
-
-	d, err := testDir(mn, tn)
-	if err != nil {
-		t.Fatalf("Cannot create temp directory/file: %v\n", err)
-	}
-	defer os.RemoveAll(d)
-
-	ts := []struct {
-		p       string
-		d, s, c bool
-		o       string
-		r       string
-		n       string
-	}{
-		{
-			mn, true, false, false, "", "",
-			"Foo (2020).abc",
-		},
-		{
-			mn, true, false, false, "target", "",
-			filepath.Join("target", "Foo (2020).abc"),
-		},
-		{
-			mn, true, true, false, "", "",
-			filepath.Join("Foo (2020)", "Foo (2020).abc"),
-		},
-		{
-			mn, true, true, false, "target", "",
-			filepath.Join("target", "Foo (2020)", "Foo (2020).abc"),
-		},
-		{
-			tn, true, false, false, "", "",
-			filepath.Join("Foo", "Season 01", "Foo - s01e02 - Bar.abc"),
-		},
-		{
-			tn, true, false, false, "target", "",
-			filepath.Join("target", "Foo", "Season 01", "Foo - s01e02 - Bar.abc"),
-		},
-		{
-			tn, true, false, false, "target", "renamed-foo",
-			filepath.Join("target", "renamed-foo", "Season 01", "Foo - s01e02 - Bar.abc"),
-		},
-		// Not dry run.
-		{
-			mn, false, true, false, filepath.Join(d, "target"), "",
-			filepath.Join(d, "target", "Foo (2020)", "Foo (2020).abc"),
-		},
-		{
-			tn, false, false, false, filepath.Join(d, "target"), "",
-			filepath.Join(d, "target", "Foo", "Season 01", "Foo - s01e02 - Bar.abc"),
-		},
-		{
-			tn, false, false, false, filepath.Join(d, "target"), "renamed-foo",
-			filepath.Join(d, "target", "renamed-foo", "Season 01", "Foo - s01e02 - Bar.abc"),
-		},
-	}
-
-	for _, tt := range ts {
-		np := convert(tt.p, tt.d, tt.s, tt.c, tt.o, tt.r)
-		if np != tt.n {
-			t.Errorf("got:  %s\nwant: %s", np, tt.n)
-		}
-		if !tt.d {
-			d, _ := filepath.Split(np)
-			if _, err := os.Stat(d); os.IsNotExist(err) {
-				t.Errorf("dir does not exist:  %v\n", err)
-			}
-		}
-	}
-}
-
 func BenchmarkParse(b *testing.B) {
-	pf := &plexFile{name: "Marvel's.Agents.of.S.H.I.E.L.D.S02E01.Shadows.1080p.WEB-DL.DD5.1", mov: movie{}}
 	for i := 0; i < b.N; i++ {
-		pf.parse()
+		parse("Marvel's.Agents.of.S.H.I.E.L.D.S02E01.Shadows.1080p.WEB-DL.DD5.1")
 	}
 }
-
-func BenchmarkConvert(b *testing.B) {
-	const n = "foo.s01e02.bar.abc"
-
-	d, err := testDir(n)
-	if err != nil {
-		b.Fatalf("Cannot create temp directory/file: %v\n", err)
-	}
-	defer os.RemoveAll(d)
-
-	for i := 0; i < b.N; i++ {
-		convert(n, false, false, false, filepath.Join(d, "target"), "")
-	}
-}
-
-func testDir(paths ...string) (string, error) {
-	d := os.TempDir()
-
-	for _, p := range paths {
-		if err := os.WriteFile(filepath.Join(d, p), nil, 0666); err != nil {
-			return "", err
-		}
-	}
-
-	return d, nil
-}
@@ -0,0 +1,31 @@
+package plexize
+
+// Version of a movie in the same folder, like "Movie (2019) - Director's Cut"
+// or "Movie (2019) - 1080p".
+const versionTemplate = `{{with .Edition}} - {{.}}{{else}}{{with .Tech.Resolution}} - {{.}}{{end}}{{end}}`
+
+// Profiles is naming templates of media servers: plex, jellyfin, emby and kodi.
+var Profiles = map[string]Templates{
+	"plex": DefaultTemplates,
+	"jellyfin": {
+		Movie:    `{{.TitleYear}}` + versionTemplate + `{{with .LanguageTag}} {{.}}{{end}}{{with .Part}} - {{.}}{{end}}`,
+		Episode:  `{{.TitleYear}} - {{upper .EpisodeTag}}{{with .EpisodeTitle}} - {{.}}{{end}}{{with .LanguageTag}} {{.}}{{end}}{{with .Part}} - {{.}}{{end}}`,
+		MovieDir: `{{.TitleYear}}{{with .ID}} [{{$.IDSource}}id-{{.}}]{{end}}`,
+		ShowDir:  `{{.TitleYear}}{{with .ID}} [{{$.IDSource}}id-{{.}}]{{end}}`,
+		Season:   `Season {{.Season}}`,
+	},
+	"emby": {
+		Movie:    `{{.TitleYear}}` + versionTemplate + `{{with .LanguageTag}} {{.}}{{end}}{{with .Part}} - {{.}}{{end}}`,
+		Episode:  `{{.TitleYear}} - {{upper .EpisodeTag}}{{with .EpisodeTitle}} - {{.}}{{end}}{{with .LanguageTag}} {{.}}{{end}}{{with .Part}} - {{.}}{{end}}`,
+		MovieDir: `{{.TitleYear}}{{with .ID}} [{{$.IDSource}}id={{.}}]{{end}}`,
+		ShowDir:  `{{.TitleYear}}{{with .ID}} [{{$.IDSource}}id={{.}}]{{end}}`,
+		Season:   `Season {{.Season}}`,
+	},
+	"kodi": {
+		Movie:    `{{.TitleYear}}{{with .Part}} - {{.}}{{end}}`,
+		Episode:  `{{.TitleYear}} {{upper .EpisodeTag}}{{with .EpisodeTitle}} {{.}}{{end}}{{with .Part}} - {{.}}{{end}}`,
+		MovieDir: `{{.TitleYear}}`,
+		ShowDir:  `{{.TitleYear}}`,
+		Season:   `{{if eq .Season "00"}}Specials{{else}}Season {{.Season}}{{end}}`,
+	},
+}
@@ -0,0 +1,68 @@
+package plexize
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestProfiles(t *testing.T) {
+	const (
+		mn = "Blade.Runner.1982.Final.Cut.1080p.mkv"
+		en = "The.Flash.2014.S01E02.Fastest.Man.Alive.mkv"
+		sn = "Doctor.Who.S00E03.mkv"
+	)
+
+	ts := []struct {
+		p          string
+		mp, ep, sp string
+	}{
+		{
+			"plex",
+			filepath.Join("Blade Runner (1982) {edition-Final Cut} {imdb-tt0083658}", "Blade Runner (1982) {edition-Final Cut}"),
+			filepath.Join("The Flash (2014) {tvdb-279121}", "Season 01", "The Flash (2014) - s01e02 - Fastest Man Alive"),
+			filepath.Join("Doctor Who", "Specials", "Doctor Who - s00e03"),
+		},
+		{
+			"jellyfin",
+			filepath.Join("Blade Runner (1982) [imdbid-tt0083658]", "Blade Runner (1982) - Final Cut"),
+			filepath.Join("The Flash (2014) [tvdbid-279121]", "Season 01", "The Flash (2014) - S01E02 - Fastest Man Alive"),
+			filepath.Join("Doctor Who", "Season 00", "Doctor Who - S00E03"),
+		},
+		{
+			"emby",
+			filepath.Join("Blade Runner (1982) [imdbid=tt0083658]", "Blade Runner (1982) - Final Cut"),
+			filepath.Join("The Flash (2014) [tvdbid=279121]", "Season 01", "The Flash (2014) - S01E02 - Fastest Man Alive"),
+			filepath.Join("Doctor Who", "Season 00", "Doctor Who - S00E03"),
+		},
+		{
+			"kodi",
+			filepath.Join("Blade Runner (1982)", "Blade Runner (1982)"),
+			filepath.Join("The Flash (2014)", "Season 01", "The Flash (2014) S01E02 Fastest Man Alive"),
+			filepath.Join("Doctor Who", "Specials", "Doctor Who S00E03"),
+		},
+	}
+
+	for _, tt := range ts {
+		n, err := NewNamer(Profiles[tt.p])
+		if err != nil {
+			t.Fatalf("Cannot create namer of %s: %v\n", tt.p, err)
+		}
+		for _, c := range []struct{ f, id, p string }{{mn, "tt0083658", tt.mp}, {en, "tvdb-279121", tt.ep}, {sn, "", tt.sp}} {
+			m, err := Parse(c.f)
+			if err != nil {
+				t.Fatalf("Cannot parse %s: %v\n", c.f, err)
+			}
+			if c.id != "" {
+				if err := m.SetID(c.id); err != nil {
+					t.Fatalf("Cannot set ID %s: %v\n", c.id, err)
+				}
+			}
+			d, _ := n.Dir(m)
+			sd, _ := n.SeasonDir(m)
+			nm, _ := n.Name(m)
+			if p := filepath.Join(d, sd, nm); p != c.p {
+				t.Errorf("%s: %s\ngot:  %s\nwant: %s", tt.p, c.f, p, c.p)
+			}
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package plexize
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Release is provenance of a scene or P2P release.
+type Release struct {
+	Group    string   // Release group, like DIMENSION or RARBG.
+	Proper   bool     // Fixes an earlier release of the same media.
+	Repack   bool     // Fixes an earlier release of the same group.
+	Real     bool     // Fixes a PROPER or REPACK, like REAL.PROPER.
+	Internal bool     // Internal release of the group.
+	Tags     []string // Scene tag suffixes, like ETRG in [ETRG].
+}
+
+// Priority ranks releases of the same media, higher is preferred. PROPER and
+// REPACK releases are preferred over earlier ones, and REAL ones over them.
+func (r Release) Priority() int {
+	p := 0
+	if r.Proper || r.Repack {
+		p++
+	}
+	if r.Real {
+		p++
+	}
+	return p
+}
+
+// Flags are matched in any case after the title only, to not match titles,
+// like "Internal Affairs".
+var (
+	properRe   = regexp.MustCompile(`(?i)^proper$`)
+	repackRe   = regexp.MustCompile(`(?i)^(?:repack|rerip)\d?$`)
+	realRe     = regexp.MustCompile(`(?i)^real$`)
+	internalRe = regexp.MustCompile(`(?i)^internal$`)
+	sceneTagRe = regexp.MustCompile(`\s*\[([^\]\[]+)\]\s*$`)
+	groupRe    = regexp.MustCompile(`([^ ._\-\[\]()]+)(?: - |-)([A-Za-z0-9]+)$`)
+)
+
+// releaseFlag sets the release flag of token i after the title, like PROPER or
+// REAL before PROPER or REPACK, and reports whether it is a flag.
+func (r *Release) releaseFlag(ts []string, i int) bool {
+	t := strings.Trim(ts[i], " -[]()")
+	switch {
+	case properRe.MatchString(t):
+		r.Proper = true
+	case repackRe.MatchString(t):
+		r.Repack = true
+	case internalRe.MatchString(t):
+		r.Internal = true
+	case realRe.MatchString(t) && i+1 < len(ts):
+		n := strings.Trim(ts[i+1], " -[]()")
+		if !properRe.MatchString(n) && !repackRe.MatchString(n) {
+			return false
+		}
+		r.Real = true
+	default:
+		return false
+	}
+	return true
+}
+
+// cutRelease cuts the release group and scene tags out of the end of the name,
+// so they would not leak into titles.
+func cutRelease(n string) (Release, string) {
+	var r Release
+
+	for {
+		t := sceneTagRe.FindStringSubmatchIndex(n)
+		if t == nil || t[0] == 0 {
+			break
+		}
+		r.Tags = append([]string{n[t[2]:t[3]]}, r.Tags...)
+		n = n[:t[0]]
+	}
+
+	// A group follows junk, like x264-DIMENSION or H.264-NTb, or is in upper
+	// case, like Pilot-LOL, so titles like Ant-Man are kept. DL of WEB-DL is not
+	// a group.
+	if g := groupRe.FindStringSubmatch(n); g != nil && (commonPatterns.match(g[1]) || g[1] == "264" || g[1] == "265" || upper(g[2])) && !strings.EqualFold(g[2], "DL") {
+		r.Group = g[2]
+		n = strings.TrimSuffix(n, g[0]) + g[1]
+	}
+
+	return r, n
+}
+
+func upper(s string) bool {
+	l := 0
+	for _, r := range s {
+		if unicode.IsLower(r) {
+			return false
+		}
+		if unicode.IsLetter(r) {
+			l++
+		}
+	}
+	return l > 1
+}
@@ -0,0 +1,122 @@
+package plexize
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRelease(t *testing.T) {
+	ts := []struct {
+		n     string
+		m, en string
+		r     Release
+	}{
+		{
+			"[ www.Speed.cd ] -Sons.of.Anarchy.S07E07.720p.HDTV.X264-DIMENSION",
+			"Sons Of Anarchy", "",
+			Release{Group: "DIMENSION"},
+		},
+		{
+			"Annabelle.2014.1080p.PROPER.HC.WEBRip.x264.AAC.2.0-RARBG",
+			"Annabelle", "",
+			Release{Group: "RARBG", Proper: true},
+		},
+		{
+			"Annabelle.2014.HC.HDRip.XViD.AC3-juggs[ETRG]",
+			"Annabelle", "",
+			Release{Group: "juggs", Tags: []string{"ETRG"}},
+		},
+		{
+			"Doctor.Who.2005.8x11.Dark.Water.720p.HDTV.x264-FoV[rartv]",
+			"Doctor Who", "Dark Water",
+			Release{Group: "FoV", Tags: []string{"rartv"}},
+		},
+		{
+			"22 Jump Street (2014) 720p BrRip x264 - YIFY",
+			"22 Jump Street", "",
+			Release{Group: "YIFY"},
+		},
+		{
+			"Show.S01E02.Pilot-LOL",
+			"Show", "Pilot",
+			Release{Group: "LOL"},
+		},
+		{
+			"Show.S01E02.REAL.PROPER.iNTERNAL.720p.HDTV.x264-GRP",
+			"Show", "",
+			Release{Group: "GRP", Proper: true, Real: true, Internal: true},
+		},
+		{
+			"Ant-Man.2015.3D.1080p.BRRip.Half-SBS.x264.AAC-m2g",
+			"Ant-Man", "",
+			Release{Group: "m2g"},
+		},
+		{
+			"Internal.Affairs.1990.720p.BluRay",
+			"Internal Affairs", "",
+			Release{},
+		},
+		{
+			"Show.S01E02.real.repack.720p",
+			"Show", "",
+			Release{Repack: true, Real: true},
+		},
+		{
+			"Movie.2019.INTERNAL.720p.BluRay",
+			"Movie", "",
+			Release{Internal: true},
+		},
+		{
+			"Movie.2019.Internal.720p.BluRay",
+			"Movie", "",
+			Release{Internal: true},
+		},
+		{
+			"Movie.2019.1080p.WEB-DL.H.264-NTb",
+			"Movie", "",
+			Release{Group: "NTb"},
+		},
+		{
+			"Show.S01E02.Keep.It.Real.720p",
+			"Show", "Keep It Real",
+			Release{},
+		},
+		{
+			"[SubsPlease] Jujutsu Kaisen - 05 [1080p][ABCD1234]",
+			"Jujutsu Kaisen", "",
+			Release{Group: "SubsPlease"},
+		},
+	}
+
+	for _, tt := range ts {
+		m := parse(tt.n)
+		if m.Title != tt.m {
+			t.Errorf("name: %s\ngot:  %s\nwant: %s", tt.n, m.Title, tt.m)
+		}
+		if m.EpisodeTitle != tt.en {
+			t.Errorf("episode name: %s\ngot:  %s\nwant: %s", tt.n, m.EpisodeTitle, tt.en)
+		}
+		if !reflect.DeepEqual(m.Release, tt.r) {
+			t.Errorf("release: %s\ngot:  %+v\nwant: %+v", tt.n, m.Release, tt.r)
+		}
+	}
+}
+
+func TestReleasePriority(t *testing.T) {
+	ts := []struct {
+		r Release
+		p int
+	}{
+		{Release{}, 0},
+		{Release{Internal: true}, 0},
+		{Release{Proper: true}, 1},
+		{Release{Repack: true}, 1},
+		{Release{Proper: true, Real: true}, 2},
+	}
+
+	for _, tt := range ts {
+		if p := tt.r.Priority(); p != tt.p {
+			t.Errorf("priority: %+v\ngot:  %d\nwant: %d", tt.r, p, tt.p)
+		}
+	}
+}
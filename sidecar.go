@@ -0,0 +1,106 @@
+package plexize
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Sidecar is a subtitle file of a video, like Movie.2019.1080p.en.forced.srt
+// for Movie.2019.1080p.mkv.
+type Sidecar struct {
+	Language string // ISO 639-1 code, like en, or with a region, like pt-BR.
+	SDH      bool   // Subtitles for the deaf and hard of hearing.
+	Forced   bool   // Forced subtitles, only for foreign parts.
+	Ext      string // Lower case extension, like .srt.
+}
+
+var sidecarExts = map[string]bool{
+	".ass": true, ".idx": true, ".smi": true, ".srt": true, ".ssa": true, ".sub": true, ".sup": true, ".vtt": true,
+}
+
+var (
+	languageCodeRe   = regexp.MustCompile(`^[a-z]{2}$`)
+	languageRegionRe = regexp.MustCompile(`^([a-z]{2})-([a-z]{2})$`)
+	trackRe          = regexp.MustCompile(`^\d{1,2}$`)
+)
+
+// ParseSidecar parses a subtitle file name sharing the stem of a video file
+// name, and reports whether it is a sidecar of the video. Tags after the stem,
+// like en, pt-BR, English, forced, sdh, hi, cc or a track number with a
+// language, are parsed in any order. Any other tag, like 720p of another
+// release or Resurrection of another title, means it is not a sidecar.
+func ParseSidecar(video, filename string) (Sidecar, bool) {
+	var sc Sidecar
+
+	_, v := filepath.Split(video)
+	v = strings.TrimSuffix(v, filepath.Ext(v))
+	_, f := filepath.Split(filename)
+	ext := filepath.Ext(f)
+	if !sidecarExts[strings.ToLower(ext)] || !strings.HasPrefix(f, v) {
+		return sc, false
+	}
+	sc.Ext = strings.ToLower(ext)
+
+	ts := strings.TrimSuffix(f[len(v):], ext)
+	if ts != "" && ts[0] != '.' {
+		return sc, false
+	}
+
+	var track bool
+	for _, t := range sidecarTags(strings.ToLower(ts)) {
+		switch {
+		case t == "forced":
+			sc.Forced = true
+		case t == "sdh" || t == "hi" || t == "cc":
+			sc.SDH = true
+		case languageNames[t] != "":
+			sc.Language = languageNames[t]
+		case languageCodeRe.MatchString(t):
+			sc.Language = t
+		case languageRegionRe.MatchString(t):
+			r := languageRegionRe.FindStringSubmatch(t)
+			sc.Language = r[1] + "-" + strings.ToUpper(r[2])
+		case trackRe.MatchString(t):
+			track = true
+		default:
+			return Sidecar{}, false
+		}
+	}
+	// A bare number is more likely a sequel, like Cars.2.srt, than a track.
+	if track && sc.Language == "" {
+		return Sidecar{}, false
+	}
+
+	return sc, true
+}
+
+// sidecarTags splits lower case sidecar tags, keeping languages with a region,
+// like pt-br, whole.
+func sidecarTags(s string) []string {
+	var ts []string
+	for _, t := range strings.FieldsFunc(s, func(r rune) bool { return r == '.' || r == '_' }) {
+		if languageRegionRe.MatchString(t) {
+			ts = append(ts, t)
+			continue
+		}
+		ts = append(ts, strings.FieldsFunc(t, func(r rune) bool { return r == '-' })...)
+	}
+	return ts
+}
+
+// Name returns the Plex sidecar file name for a video file stem, like
+// "Movie (2019).en.forced.srt".
+func (sc Sidecar) Name(stem string) string {
+	n := stem
+	if sc.Language != "" {
+		n += "." + sc.Language
+	}
+	if sc.SDH {
+		n += ".sdh"
+	}
+	if sc.Forced {
+		n += ".forced"
+	}
+	return n + sc.Ext
+}
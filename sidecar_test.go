@@ -0,0 +1,62 @@
+package plexize
+
+import "testing"
+
+func TestParseSidecar(t *testing.T) {
+	const v = "Movie.2019.1080p.mkv"
+
+	ts := []struct {
+		f  string
+		ok bool
+		sc Sidecar
+		n  string
+	}{
+		{"Movie.2019.1080p.srt", true, Sidecar{Ext: ".srt"}, "Movie (2019).srt"},
+		{"Movie.2019.1080p.en.srt", true, Sidecar{Language: "en", Ext: ".srt"}, "Movie (2019).en.srt"},
+		{"Movie.2019.1080p.English.forced.SRT", true, Sidecar{Language: "en", Forced: true, Ext: ".srt"}, "Movie (2019).en.forced.srt"},
+		{"Movie.2019.1080p.eng.sdh.srt", true, Sidecar{Language: "en", SDH: true, Ext: ".srt"}, "Movie (2019).en.sdh.srt"},
+		{"Movie.2019.1080p.forced.en.ass", true, Sidecar{Language: "en", Forced: true, Ext: ".ass"}, "Movie (2019).en.forced.ass"},
+		{"Movie.2019.1080p.fr.idx", true, Sidecar{Language: "fr", Ext: ".idx"}, "Movie (2019).fr.idx"},
+		{"Movie.2019.1080p.fr.sub", true, Sidecar{Language: "fr", Ext: ".sub"}, "Movie (2019).fr.sub"},
+		{"Movie.2019.1080p.nfo", false, Sidecar{}, ""},
+		{"Movie.2019.720p.en.srt", false, Sidecar{}, ""},
+		{"Movie.2019.1080px.srt", false, Sidecar{}, ""},
+		{"Movie.2019.1080p.pt-BR.srt", true, Sidecar{Language: "pt-BR", Ext: ".srt"}, "Movie (2019).pt-BR.srt"},
+		{"Movie.2019.1080p.en-forced.srt", true, Sidecar{Language: "en", Forced: true, Ext: ".srt"}, "Movie (2019).en.forced.srt"},
+		{"Movie.2019.1080p.2.en.srt", true, Sidecar{Language: "en", Ext: ".srt"}, "Movie (2019).en.srt"},
+		{"Movie.2019.1080p.2.srt", false, Sidecar{}, ""},
+		{"Movie.2019.1080p.Director.Cut.en.srt", false, Sidecar{}, ""},
+	}
+
+	for _, tt := range ts {
+		sc, ok := ParseSidecar(v, tt.f)
+		if ok != tt.ok {
+			t.Errorf("sidecar: %s\ngot:  %v\nwant: %v", tt.f, ok, tt.ok)
+		}
+		if !ok {
+			continue
+		}
+		if sc != tt.sc {
+			t.Errorf("sidecar: %s\ngot:  %+v\nwant: %+v", tt.f, sc, tt.sc)
+		}
+		if n := sc.Name("Movie (2019)"); n != tt.n {
+			t.Errorf("name: %s\ngot:  %s\nwant: %s", tt.f, n, tt.n)
+		}
+	}
+}
+
+func TestParseSidecarOther(t *testing.T) {
+	ts := []struct {
+		v, f string
+	}{
+		{"Alien.mkv", "Alien.Resurrection.en.srt"},
+		{"Cars.mkv", "Cars.2.srt"},
+		{"Movie.2019.mkv", "Movie.2019.720p.en.srt"},
+	}
+
+	for _, tt := range ts {
+		if _, ok := ParseSidecar(tt.v, tt.f); ok {
+			t.Errorf("sidecar: %s of %s\ngot:  %v\nwant: %v", tt.f, tt.v, ok, false)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package plexize
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// Specials maps special episodes of TV shows to their season zero episode
+// numbers, keyed by show title and special title.
+type Specials map[string]string
+
+// LoadSpecials reads a specials list. Each line has a show title, a special
+// title and its episode number separated by "|", like:
+//
+//	Doctor Who | The Christmas Invasion | 2
+//
+// Empty lines and lines starting with "#" are ignored.
+func LoadSpecials(r io.Reader) (Specials, error) {
+	sp := Specials{}
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		l := strings.TrimSpace(scanner.Text())
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+
+		fs := strings.Split(l, "|")
+		if len(fs) != 3 {
+			return nil, fmt.Errorf("plexize: invalid specials line %d: %q", n, l)
+		}
+		e := strings.TrimSpace(fs[2])
+		if !numberRe.MatchString(e) {
+			return nil, fmt.Errorf("plexize: invalid special episode number on line %d: %q", n, e)
+		}
+		sp[specialKey(fs[0], fs[1])] = fmt.Sprintf("%02v", e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return sp, nil
+}
+
+// Map sets the season zero episode number of a special from the list. Media
+// which is not a special, or has an episode number already, is not changed.
+func (sp Specials) Map(m Media) Media {
+	if m.Season != "00" || m.Episode != "" {
+		return m
+	}
+
+	if e, ok := sp[specialKey(m.Title, m.EpisodeTitle)]; ok {
+		m.Episode = e
+	}
+
+	return m
+}
+
+func specialKey(show, title string) string {
+	f := func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsNumber(r) }
+	return strings.ToLower(strings.Join(strings.FieldsFunc(show, f), " ") + "|" + strings.Join(strings.FieldsFunc(title, f), " "))
+}
@@ -0,0 +1,133 @@
+package plexize
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseSpecial(t *testing.T) {
+	ts := []struct {
+		n           string
+		m, s, e, en string
+		pn, sd      string
+	}{
+		{
+			"Doctor.Who.S00E03.720p.HDTV",
+			"Doctor Who", "00", "03", "",
+			"Doctor Who - s00e03", "Specials",
+		},
+		{
+			"Sherlock.SP01.The.Abominable.Bride.1080p",
+			"Sherlock", "00", "01", "The Abominable Bride",
+			"Sherlock - s00e01 - The Abominable Bride", "Specials",
+		},
+		{
+			"Doctor.Who.Special.The.Christmas.Invasion.720p",
+			"Doctor Who", "00", "", "The Christmas Invasion",
+			"Doctor Who - s00 - The Christmas Invasion", "Specials",
+		},
+		{
+			"Show OVA 2 720p",
+			"Show", "00", "02", "",
+			"Show - s00e02", "Specials",
+		},
+		{
+			"[Group] Show OVA - 02 [1080p]",
+			"Show", "00", "02", "",
+			"Show - s00e02", "Specials",
+		},
+		{
+			"Special.Correspondents.2016.720p",
+			"Special Correspondents", "", "", "",
+			"Special Correspondents (2016)", "",
+		},
+		{
+			"Aliens.1986.Special.Edition.1080p.BluRay.mkv",
+			"Aliens", "", "", "",
+			"Aliens (1986) {edition-Special Edition}", "",
+		},
+		{
+			"Movie.Special.Features.2019.720p",
+			"Movie Special Features", "", "", "",
+			"Movie Special Features (2019)", "",
+		},
+		{
+			"The.Special.Relationship.2010.720p",
+			"The Special Relationship", "", "", "",
+			"The Special Relationship (2010)", "",
+		},
+		{
+			"The.Specials.2000",
+			"The Specials", "", "", "",
+			"The Specials (2000)", "",
+		},
+		{
+			"Very.Special.Episode.S01E01",
+			"Very Special Episode", "01", "01", "",
+			"Very Special Episode - s01e01", "Season 01",
+		},
+		{
+			"Movie.2019.Special.720p",
+			"Movie Special", "", "", "",
+			"Movie Special (2019)", "",
+		},
+	}
+
+	for _, tt := range ts {
+		m := parse(tt.n)
+		if m.Title != tt.m {
+			t.Errorf("name: %s\ngot:  %s\nwant: %s", tt.n, m.Title, tt.m)
+		}
+		if m.Season != tt.s {
+			t.Errorf("season: %s\ngot:  %s\nwant: %s", tt.n, m.Season, tt.s)
+		}
+		if m.Episode != tt.e {
+			t.Errorf("episode: %s\ngot:  %s\nwant: %s", tt.n, m.Episode, tt.e)
+		}
+		if m.EpisodeTitle != tt.en {
+			t.Errorf("episode name: %s\ngot:  %s\nwant: %s", tt.n, m.EpisodeTitle, tt.en)
+		}
+		if pn := m.Name(); pn != tt.pn {
+			t.Errorf("plex name: %s\ngot:  %s\nwant: %s", tt.n, pn, tt.pn)
+		}
+		if sd := m.SeasonDir(); sd != tt.sd {
+			t.Errorf("season dir: %s\ngot:  %s\nwant: %s", tt.n, sd, tt.sd)
+		}
+	}
+}
+
+func TestSpecials(t *testing.T) {
+	const l = `# show | special | episode
+Doctor Who | The Christmas Invasion | 2
+
+Sherlock|The Abominable Bride|1
+`
+
+	sp, err := LoadSpecials(strings.NewReader(l))
+	if err != nil {
+		t.Fatalf("Cannot load specials: %v\n", err)
+	}
+
+	ts := []struct {
+		m Media
+		e string
+	}{
+		{Media{Title: "Doctor Who", Season: "00", EpisodeTitle: "The Christmas Invasion"}, "02"},
+		{Media{Title: "doctor who", Season: "00", EpisodeTitle: "The Christmas-Invasion"}, "02"},
+		{Media{Title: "Sherlock", Season: "00", Episode: "05", EpisodeTitle: "The Abominable Bride"}, "05"},
+		{Media{Title: "Sherlock", Season: "01", Episode: "01", EpisodeTitle: "The Abominable Bride"}, "01"},
+		{Media{Title: "Doctor Who", Season: "00", EpisodeTitle: "Unknown"}, ""},
+	}
+
+	for _, tt := range ts {
+		if e := sp.Map(tt.m).Episode; e != tt.e {
+			t.Errorf("episode: %+v\ngot:  %s\nwant: %s", tt.m, e, tt.e)
+		}
+	}
+
+	for _, l := range []string{"Doctor Who | 2", "Doctor Who | The Christmas Invasion | two"} {
+		if _, err := LoadSpecials(strings.NewReader(l)); err == nil {
+			t.Errorf("error: %s\ngot:  %v\nwant: error", l, err)
+		}
+	}
+}
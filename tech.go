@@ -0,0 +1,150 @@
+package plexize
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Tech is technical attributes of a release.
+type Tech struct {
+	Resolution string // Like 720p, 1080p or 2160p.
+	Source     string // Like BluRay, WEB-DL or HDTV.
+	Codec      string // Video codec, like x264, x265 or HEVC.
+	Audio      string // Like DTS, AC3 or AAC 2.0.
+	BitDepth   string // Like 10bit.
+	HDR        string // Like HDR, HDR10+, DV or DV HDR10.
+	ThreeD     string // Like 3D, SBS or Half-SBS.
+}
+
+type techPattern struct {
+	re   *regexp.Regexp
+	name string
+}
+
+// Patterns are ordered, the first match wins. Names are matched after replacing
+// underscores with spaces, so \b works for all separators.
+var (
+	resolutionRe = regexp.MustCompile(`(?i)\b(480|576|720|1080|2160|4320)[pi]\b|\b(4K|UHD)\b`)
+
+	sourcePatterns = [...]techPattern{
+		{regexp.MustCompile(`(?i)\bblu-?ray\b|\bbd-?remux\b`), "BluRay"},
+		{regexp.MustCompile(`(?i)\bbd-?rip\b`), "BDRip"},
+		{regexp.MustCompile(`(?i)\bbr-?rip\b`), "BRRip"},
+		{regexp.MustCompile(`(?i)\bweb[ .\-]?dl\b`), "WEB-DL"},
+		{regexp.MustCompile(`(?i)\bweb-?rip\b`), "WEBRip"},
+		{regexp.MustCompile(`\bWEB\b`), "WEB"},
+		{regexp.MustCompile(`(?i)\bhdtv\b`), "HDTV"},
+		{regexp.MustCompile(`(?i)\bpdtv\b`), "PDTV"},
+		{regexp.MustCompile(`(?i)\bdvd-?rip\b`), "DVDRip"},
+		{regexp.MustCompile(`(?i)\bdvd-?scr\b`), "DVDScr"},
+		{regexp.MustCompile(`(?i)\bdvd\b`), "DVD"},
+		{regexp.MustCompile(`(?i)\bhd-?rip\b`), "HDRip"},
+		{regexp.MustCompile(`(?i:\bhd-?ts\b|\btelesync\b)|\bTS\b`), "TS"},
+		{regexp.MustCompile(`(?i:\bhd-?cam\b|\bcam-?rip\b)|\bCAM\b`), "CAM"},
+	}
+
+	codecPatterns = [...]techPattern{
+		{regexp.MustCompile(`(?i)\bx264\b`), "x264"},
+		{regexp.MustCompile(`(?i)\bx265\b`), "x265"},
+		{regexp.MustCompile(`(?i)\bh[ .]?264\b`), "H.264"},
+		{regexp.MustCompile(`(?i)\bh[ .]?265\b`), "H.265"},
+		{regexp.MustCompile(`(?i)\bhevc\b`), "HEVC"},
+		{regexp.MustCompile(`(?i)\bavc\b`), "AVC"},
+		{regexp.MustCompile(`(?i)\bav1\b`), "AV1"},
+		{regexp.MustCompile(`(?i)\bxvid\b`), "XviD"},
+		{regexp.MustCompile(`(?i)\bdivx\b`), "DivX"},
+	}
+
+	audioPatterns = [...]techPattern{
+		{audioRe(`dts[ .\-]?hd(?:[ .\-]?ma)?`), "DTS-HD MA"},
+		{audioRe(`true-?hd`), "TrueHD"},
+		{audioRe(`ddp|dd\+|e-?ac-?3`), "DD+"},
+		{audioRe(`dts`), "DTS"},
+		{audioRe(`dd`), "DD"},
+		{audioRe(`ac-?3`), "AC3"},
+		{audioRe(`aac`), "AAC"},
+		{audioRe(`flac`), "FLAC"},
+		{audioRe(`opus`), "Opus"},
+		{audioRe(`mp3`), "MP3"},
+	}
+	atmosRe = regexp.MustCompile(`(?i)\batmos\b`)
+
+	bitDepthRe = regexp.MustCompile(`(?i)\b(8|10|12)[ .\-]?bits?\b`)
+
+	dvRe        = regexp.MustCompile(`(?i)\b(?:dv|dovi|dolby[ .]?vision)\b`)
+	hdrPatterns = [...]techPattern{
+		{regexp.MustCompile(`(?i)\bhdr10(?:\+|plus)`), "HDR10+"},
+		{regexp.MustCompile(`(?i)\bhdr10\b`), "HDR10"},
+		{regexp.MustCompile(`(?i)\bhdr\b`), "HDR"},
+		{regexp.MustCompile(`(?i)\bhlg\b`), "HLG"},
+	}
+
+	threeDPatterns = [...]techPattern{
+		{regexp.MustCompile(`(?i)\bhalf-?sbs\b|\bhsbs\b`), "Half-SBS"},
+		{regexp.MustCompile(`(?i)\bsbs\b`), "SBS"},
+		{regexp.MustCompile(`(?i)\bhalf-?ou\b|\bhou\b`), "Half-OU"},
+		{regexp.MustCompile(`\bOU\b`), "OU"},
+		{regexp.MustCompile(`\b3D\b`), "3D"},
+	}
+)
+
+func parseTech(n string) Tech {
+	var t Tech
+
+	n = strings.ReplaceAll(n, "_", " ")
+
+	if r := resolutionRe.FindStringSubmatch(n); r != nil {
+		if r[1] != "" {
+			t.Resolution = r[1] + "p"
+		} else {
+			t.Resolution = "2160p"
+		}
+	}
+
+	t.Source = firstMatch(sourcePatterns[:], n)
+	t.Codec = firstMatch(codecPatterns[:], n)
+
+	for _, p := range audioPatterns {
+		a := p.re.FindStringSubmatch(n)
+		if a == nil {
+			continue
+		}
+		t.Audio = p.name
+		if a[1] != "" {
+			t.Audio += " " + a[1] + "." + a[2]
+		}
+		break
+	}
+	if atmosRe.MatchString(n) {
+		t.Audio = strings.TrimSpace(t.Audio + " Atmos")
+	}
+
+	if b := bitDepthRe.FindStringSubmatch(n); b != nil {
+		t.BitDepth = b[1] + "bit"
+	}
+
+	// Dolby Vision releases could have an HDR layer too, like "DV HDR10".
+	t.HDR = firstMatch(hdrPatterns[:], n)
+	if dvRe.MatchString(n) {
+		t.HDR = strings.TrimSpace("DV " + t.HDR)
+	}
+
+	t.ThreeD = firstMatch(threeDPatterns[:], n)
+
+	return t
+}
+
+// audioRe returns an audio codec pattern, with optional channels like 5.1
+// glued or separated, as submatches.
+func audioRe(c string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)(?:^|[^a-z0-9])(?:` + c + `)(?:[ .\-]?([1-7])[ .]([01]))?(?:[^a-z0-9]|$)`)
+}
+
+func firstMatch(ps []techPattern, n string) string {
+	for _, p := range ps {
+		if p.re.MatchString(n) {
+			return p.name
+		}
+	}
+	return ""
+}
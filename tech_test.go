@@ -0,0 +1,65 @@
+package plexize
+
+import "testing"
+
+func TestParseTech(t *testing.T) {
+	ts := []struct {
+		n  string
+		tc Tech
+	}{
+		{
+			"[ www.Speed.cd ] -Sons.of.Anarchy.S07E07.720p.HDTV.X264-DIMENSION",
+			Tech{Resolution: "720p", Source: "HDTV", Codec: "x264"},
+		},
+		{
+			"Annabelle.2014.1080p.PROPER.HC.WEBRip.x264.AAC.2.0-RARBG",
+			Tech{Resolution: "1080p", Source: "WEBRip", Codec: "x264", Audio: "AAC 2.0"},
+		},
+		{
+			"Ant-Man.2015.3D.1080p.BRRip.Half-SBS.x264.AAC-m2g",
+			Tech{Resolution: "1080p", Source: "BRRip", Codec: "x264", Audio: "AAC", ThreeD: "Half-SBS"},
+		},
+		{
+			"Deadpool_And_Wolverine_2024_720P_Web_Dl_Ddp5_1_Atmos_H_264_Flux",
+			Tech{Resolution: "720p", Source: "WEB-DL", Codec: "H.264", Audio: "DD+ 5.1 Atmos"},
+		},
+		{
+			"Dune.Part.Two.2024.2160p.UHD.BluRay.DV.HDR10.10bit.x265.DTS-HD.MA.7.1",
+			Tech{Resolution: "2160p", Source: "BluRay", Codec: "x265", Audio: "DTS-HD MA 7.1", BitDepth: "10bit", HDR: "DV HDR10"},
+		},
+		{
+			"American.Gods.S01E01.1080p.HEVC.x265-MeGusta",
+			Tech{Resolution: "1080p", Codec: "x265"},
+		},
+		{
+			"Brave.2012.R5.DVDRip.XViD.LiNE-UNiQUE",
+			Tech{Source: "DVDRip", Codec: "XviD"},
+		},
+		{
+			"Ben Hur 2016 TELESYNC x264 AC3 MAXPRO",
+			Tech{Source: "TS", Codec: "x264", Audio: "AC3"},
+		},
+		{
+			"Cam 2018 720p WEB H264",
+			Tech{Resolution: "720p", Source: "WEB", Codec: "H.264"},
+		},
+		{
+			"The.DV.Files.2019.1080p.WEBRip",
+			Tech{Resolution: "1080p", Source: "WEBRip"},
+		},
+		{
+			"Sonic.Dv",
+			Tech{},
+		},
+		{
+			"Movie.2019.UHD.Atmos.BluRay",
+			Tech{Resolution: "2160p", Source: "BluRay", Audio: "Atmos"},
+		},
+	}
+
+	for _, tt := range ts {
+		if tc := parse(tt.n).Tech; tc != tt.tc {
+			t.Errorf("tech: %s\ngot:  %+v\nwant: %+v", tt.n, tc, tt.tc)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package plexize
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Templates is naming templates of media in Go text/template syntax, for movie
+// and episode file names (without extension), movie and TV show folders, and
+// season folders. Templates are executed with all fields of Media, and:
+//
+//	.FullTitle    Title and year, with the edition of movies, like "Blade Runner (1982) {edition-Final Cut}"
+//	.TitleYear    Title and year, like "Blade Runner (1982)"
+//	.EpisodeTag   Like s01e02, s01e02-e04, e05, s00 or 2014-03-17
+//	.IDTag        External ID in Plex tag syntax, like {imdb-tt1234567}
+//	.IDSource     Source of the external ID, imdb, tmdb or tvdb (TVDb is preferred for TV shows, and IMDb for movies)
+//	.ID           External ID, like tt1234567
+//	.LanguageTag  Languages, like [fr][sub-en], only if languages are asked
+//
+// Functions upper, lower, pad (pad 2 "1" is 01) and num (num "01" is 1) can
+// be used too. Empty templates are the default templates. Path separators of
+// field values are replaced by dashes, so only templates make folders, like
+// {{.Title}}/{{.Year}}.
+type Templates struct {
+	Movie, Episode    string
+	MovieDir, ShowDir string
+	Season            string
+}
+
+// DefaultTemplates is the Plex naming templates.
+var DefaultTemplates = Templates{
+	Movie:    `{{.FullTitle}}{{with .LanguageTag}} {{.}}{{end}}{{with .Part}} - {{.}}{{end}}`,
+	Episode:  `{{.FullTitle}} - {{.EpisodeTag}}{{with .EpisodeTitle}} - {{.}}{{end}}{{with .LanguageTag}} {{.}}{{end}}{{with .Part}} - {{.}}{{end}}`,
+	MovieDir: `{{.FullTitle}}{{with .IDTag}} {{.}}{{end}}`,
+	ShowDir:  `{{.FullTitle}}{{with .IDTag}} {{.}}{{end}}`,
+	Season:   `{{if eq .Season "00"}}Specials{{else}}Season {{.Season}}{{end}}`,
+}
+
+// pathSeparators are replaced by dashes in field values, like AC/DC, not to
+// make folders out of names.
+const pathSeparators = `/\`
+
+var templateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"pad": func(n int, s string) string {
+		return fmt.Sprintf("%0*s", n, s)
+	},
+	"num": func(s string) string {
+		if t := strings.TrimLeft(s, "0"); t != "" || s == "" {
+			return t
+		}
+		return "0"
+	},
+}
+
+// Namer names media by naming templates. A nil Namer uses the default
+// templates.
+type Namer struct {
+	movie, episode, movieDir, showDir, season *template.Template
+}
+
+var defaultNamer = MustNamer(DefaultTemplates)
+
+type templateData struct {
+	Media
+	FullTitle, TitleYear, EpisodeTag string
+	IDTag, IDSource, ID, LanguageTag string
+}
+
+// NewNamer parses naming templates, and checks them by naming sample media.
+func NewNamer(t Templates) (*Namer, error) {
+	var n Namer
+	for _, p := range []struct {
+		t    **template.Template
+		name string
+		src  string
+		def  string
+	}{
+		{&n.movie, "movie", t.Movie, DefaultTemplates.Movie},
+		{&n.episode, "episode", t.Episode, DefaultTemplates.Episode},
+		{&n.movieDir, "movie-dir", t.MovieDir, DefaultTemplates.MovieDir},
+		{&n.showDir, "show-dir", t.ShowDir, DefaultTemplates.ShowDir},
+		{&n.season, "season", t.Season, DefaultTemplates.Season},
+	} {
+		if p.src == "" {
+			p.src = p.def
+		}
+		tpl, err := template.New(p.name).Funcs(templateFuncs).Parse(p.src)
+		if err != nil {
+			return nil, err
+		}
+		*p.t = tpl
+	}
+
+	sample := Media{Title: "Title", Year: "2000", Season: "01", Episode: "02", EpisodeTitle: "Episode"}
+	for _, m := range []Media{sample, {Title: "Title", Year: "2000"}} {
+		if _, err := n.name(m, true); err != nil {
+			return nil, err
+		}
+		if _, err := n.dir(m); err != nil {
+			return nil, err
+		}
+		if _, err := n.seasonDir(m); err != nil {
+			return nil, err
+		}
+	}
+
+	return &n, nil
+}
+
+// MustNamer is like NewNamer but panics if the templates are invalid.
+func MustNamer(t Templates) *Namer {
+	n, err := NewNamer(t)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// Name returns the file name of the media, without extension.
+func (n *Namer) Name(m Media) (string, error) {
+	if n == nil {
+		n = defaultNamer
+	}
+	return n.name(m, false)
+}
+
+// NameWithLanguages returns the file name of the media like Name, with audio
+// and subtitle languages.
+func (n *Namer) NameWithLanguages(m Media) (string, error) {
+	if n == nil {
+		n = defaultNamer
+	}
+	return n.name(m, true)
+}
+
+func (n *Namer) name(m Media, languages bool) (string, error) {
+	if m.Title == "" {
+		return "", nil
+	}
+
+	t := n.movie
+	if m.IsEpisode() {
+		t = n.episode
+	}
+	d := data(m)
+	if languages {
+		d.LanguageTag = m.Languages.Tag()
+	}
+	return execute(t, d)
+}
+
+// Dir returns the movie or TV show folder name of the media.
+func (n *Namer) Dir(m Media) (string, error) {
+	if n == nil {
+		n = defaultNamer
+	}
+	return n.dir(m)
+}
+
+func (n *Namer) dir(m Media) (string, error) {
+	if m.Title == "" {
+		return "", nil
+	}
+
+	if m.IsEpisode() {
+		return execute(n.showDir, data(m))
+	}
+	return execute(n.movieDir, data(m))
+}
+
+// SeasonDir returns the season folder name of the media, or empty if the media
+// is not a TV show episode.
+func (n *Namer) SeasonDir(m Media) (string, error) {
+	if n == nil {
+		n = defaultNamer
+	}
+	return n.seasonDir(m)
+}
+
+func (n *Namer) seasonDir(m Media) (string, error) {
+	if m.Season == "" {
+		return "", nil
+	}
+
+	return execute(n.season, data(m))
+}
+
+func data(m Media) templateData {
+	for _, f := range []*string{&m.Title, &m.Year, &m.Season, &m.Episode, &m.Date, &m.EpisodeTitle, &m.Revision, &m.CRC, &m.Part, &m.Edition, &m.Release.Group} {
+		*f = strings.Map(func(r rune) rune {
+			if strings.ContainsRune(pathSeparators, r) {
+				return '-'
+			}
+			return r
+		}, *f)
+	}
+	d := templateData{Media: m, FullTitle: m.title(), TitleYear: m.Title, EpisodeTag: m.episode(), IDTag: m.idTag()}
+	if m.Year != "" {
+		d.TitleYear = fmt.Sprintf("%s (%s)", m.Title, m.Year)
+	}
+	d.IDSource, d.ID = m.id()
+	return d
+}
+
+// execute executes a naming template, and checks the name is a path of the
+// folders of the template itself, like {{.Title}}/{{.Year}}, without . or ..
+// folders.
+func execute(t *template.Template, d templateData) (string, error) {
+	var b strings.Builder
+	if err := t.Execute(&b, d); err != nil {
+		return "", err
+	}
+	n := b.String()
+	if strings.ContainsAny(n, pathSeparators) && !strings.ContainsAny(t.Root.String(), pathSeparators) {
+		return "", fmt.Errorf("plexize: invalid %s name %q", t.Name(), n)
+	}
+	for _, s := range strings.FieldsFunc(n, func(r rune) bool { return strings.ContainsRune(pathSeparators, r) }) {
+		if s == "." || s == ".." {
+			return "", fmt.Errorf("plexize: invalid %s name %q", t.Name(), n)
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,97 @@
+package plexize
+
+import "testing"
+
+func TestNamer(t *testing.T) {
+	n, err := NewNamer(Templates{
+		Episode: `{{.Title}} - {{upper .EpisodeTag}}{{with .EpisodeTitle}} - {{.}}{{end}}`,
+		ShowDir: `{{.Title}}{{with .IDs.TVDb}} [tvdbid-{{.}}]{{end}}`,
+		Season:  `Season {{num .Season}}`,
+		Movie:   `{{.FullTitle}}{{with .Tech.Resolution}} - {{.}}{{end}}`,
+	})
+	if err != nil {
+		t.Fatalf("Cannot create namer: %v\n", err)
+	}
+
+	ts := []struct {
+		f           string
+		n, d, sd    string
+		dn, dd, dsd string
+	}{
+		{
+			"The.Flash.2014.S01E02.Fastest.Man.Alive.mkv",
+			"The Flash - S01E02 - Fastest Man Alive", "The Flash", "Season 1",
+			"The Flash (2014) - s01e02 - Fastest Man Alive", "The Flash (2014)", "Season 01",
+		},
+		{
+			"Doctor.Who.S00E03.mkv",
+			"Doctor Who - S00E03", "Doctor Who", "Season 0",
+			"Doctor Who - s00e03", "Doctor Who", "Specials",
+		},
+		{
+			"The.Platform.2019.1080p.mkv",
+			"The Platform (2019) - 1080p", "The Platform (2019)", "",
+			"The Platform (2019)", "The Platform (2019)", "",
+		},
+	}
+
+	for _, tt := range ts {
+		m, err := Parse(tt.f)
+		if err != nil {
+			t.Fatalf("Cannot parse %s: %v\n", tt.f, err)
+		}
+		for _, c := range []struct {
+			n         *Namer
+			nm, d, sd string
+		}{{n, tt.n, tt.d, tt.sd}, {nil, tt.dn, tt.dd, tt.dsd}} {
+			if nm, _ := c.n.Name(m); nm != c.nm {
+				t.Errorf("name: %s\ngot:  %s\nwant: %s", tt.f, nm, c.nm)
+			}
+			if d, _ := c.n.Dir(m); d != c.d {
+				t.Errorf("dir: %s\ngot:  %s\nwant: %s", tt.f, d, c.d)
+			}
+			if sd, _ := c.n.SeasonDir(m); sd != c.sd {
+				t.Errorf("season dir: %s\ngot:  %s\nwant: %s", tt.f, sd, c.sd)
+			}
+		}
+	}
+
+	for _, tpl := range []Templates{{Movie: `{{.Title`}, {Season: `{{.Unknown}}`}, {Episode: `{{pad "x" .Season}}`}} {
+		if _, err := NewNamer(tpl); err == nil {
+			t.Errorf("namer: %+v\ngot:  nil\nwant: error", tpl)
+		}
+	}
+}
+
+func TestNamerPaths(t *testing.T) {
+	n, err := NewNamer(Templates{MovieDir: `{{.Title}}/{{.Year}}`, Movie: `{{.Title}}{{with .Edition}}-{{.}}{{end}}`})
+	if err != nil {
+		t.Fatalf("Cannot create namer: %v\n", err)
+	}
+
+	ts := []struct {
+		m    Media
+		n, d string
+		err  bool
+	}{
+		{Media{Title: "AC/DC Live", Year: "1992"}, "AC-DC Live", "AC-DC Live/1992", false},
+		{Media{Title: `Movie\..\..`, Year: "2019", Edition: "../x"}, `Movie-..-..-..-x`, "Movie-..-../2019", false},
+		{Media{Title: "..", Year: "2019"}, "", "", true},
+		{Media{Title: "Movie", Year: "2019", Edition: "."}, "Movie-.", "Movie/2019", false},
+	}
+
+	for _, tt := range ts {
+		nm, err := n.Name(tt.m)
+		if nm != tt.n || (err != nil) != tt.err {
+			t.Errorf("name: %+v\ngot:  %s %v\nwant: %s", tt.m, nm, err, tt.n)
+		}
+		d, err := n.Dir(tt.m)
+		if d != tt.d || (err != nil) != tt.err {
+			t.Errorf("dir: %+v\ngot:  %s %v\nwant: %s", tt.m, d, err, tt.d)
+		}
+	}
+
+	if nm, err := (*Namer)(nil).Name(Media{Title: "../../etc", Year: "2019"}); err != nil || nm != "..-..-etc (2019)" {
+		t.Errorf("name: ../../etc\ngot:  %s %v\nwant: ..-..-etc (2019)", nm, err)
+	}
+}